@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+)
+
+// maxCompletionValues 协议规定一次最多返回 100 个补全
+const maxCompletionValues = 100
+
+// importPathArgs 需要补全 import path 的参数名
+// mcp 的补全只支持 prompt 和 resource template，getPackageInfo 的 pkgName 是给支持按参数名补全的客户端用的
+var importPathArgs = map[string]bool{
+	"pkgName":    true,
+	"importPath": true,
+}
+
+func complete(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	arg := req.Params.Argument
+	if !importPathArgs[arg.Name] {
+		return &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: []string{}}}, nil
+	}
+
+	values, total := godoc.CompleteImportPath(arg.Value, maxCompletionValues)
+	if values == nil {
+		values = []string{}
+	}
+	return &mcp.CompleteResult{
+		Completion: mcp.CompletionResultDetails{
+			Values:  values,
+			Total:   total,
+			HasMore: total > len(values),
+		},
+	}, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+	"gopkg.in/yaml.v3"
+)
+
+// 配置的优先级: 命令行参数 > 环境变量 > 配置文件 > 默认值
+const (
+	envConfig           = "GODOC_MCP_CONFIG"
+	envTransport        = "GODOC_MCP_TRANSPORT"
+	envAddr             = "GODOC_MCP_ADDR"
+	envDescriptions     = "GODOC_MCP_DESCRIPTIONS"
+	envToolTimeout      = "GODOC_MCP_TOOL_TIMEOUT"
+	envLogLevel         = "GODOC_MCP_LOG_LEVEL"
+	envBaseURL          = "GODOC_MCP_BASE_URL"
+	envTimeout          = "GODOC_MCP_TIMEOUT"
+	envProxy            = "GODOC_MCP_PROXY"
+	envUserAgent        = "GODOC_MCP_USER_AGENT"
+	envBackend          = "GODOC_MCP_BACKEND"
+	envVulnDB           = "GODOC_MCP_VULN_DB"
+	envCacheNumCounters = "GODOC_MCP_CACHE_NUM_COUNTERS"
+	envCacheMaxCost     = "GODOC_MCP_CACHE_MAX_COST"
+	envCacheBufferItems = "GODOC_MCP_CACHE_BUFFER_ITEMS"
+	envCacheTTL         = "GODOC_MCP_CACHE_TTL"
+	envCacheMaxTTL      = "GODOC_MCP_CACHE_MAX_TTL"
+)
+
+type config struct {
+	Server serverConfig `yaml:"server"`
+	Godoc  godoc.Config `yaml:"godoc"`
+}
+
+type serverConfig struct {
+	// Transport stdio, http 或者 sse
+	Transport string `yaml:"transport"`
+	// Addr http 和 sse 模式下监听的地址
+	Addr string `yaml:"addr"`
+	// Descriptions 工具描述的文件或者目录，为空则使用内置的描述
+	Descriptions string `yaml:"descriptions"`
+	// ToolTimeout 每次工具调用的超时时间，0s 表示不超时
+	ToolTimeout time.Duration `yaml:"toolTimeout"`
+	// ToolTimeouts 按工具名单独设置超时时间，覆盖 ToolTimeout
+	ToolTimeouts map[string]time.Duration `yaml:"toolTimeouts"`
+	// LogLevel 写到 stderr 的日志级别，debug, info, warn 或 error
+	// 发给客户端的日志级别由客户端通过 logging/setLevel 设置
+	LogLevel slog.Level `yaml:"logLevel"`
+}
+
+func (c serverConfig) toolTimeout(name string) time.Duration {
+	if d, ok := c.ToolTimeouts[name]; ok {
+		return d
+	}
+	return c.ToolTimeout
+}
+
+func defaultConfig() config {
+	return config{
+		Server: serverConfig{
+			Transport:   "stdio",
+			Addr:        "127.0.0.1:8080",
+			ToolTimeout: time.Minute,
+			LogLevel:    slog.LevelInfo,
+		},
+		Godoc: godoc.DefaultConfig(),
+	}
+}
+
+// loadConfig 读取配置文件并用环境变量覆盖，path 为空时只使用默认值和环境变量
+// 配置文件是 yaml 格式，因为 json 是 yaml 的子集，所以也可以直接使用 json
+func loadConfig(path string) (config, error) {
+	cfg := defaultConfig()
+	if path == "" {
+		path = os.Getenv(envConfig)
+	}
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, errors.WithStack(err)
+		}
+		if err := yaml.Unmarshal(b, &cfg); err != nil {
+			return cfg, errors.Wrapf(err, "parse config file %s failed", path)
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func applyEnv(cfg *config) error {
+	envString(envTransport, &cfg.Server.Transport)
+	envString(envAddr, &cfg.Server.Addr)
+	envString(envDescriptions, &cfg.Server.Descriptions)
+	if v, ok := os.LookupEnv(envLogLevel); ok {
+		if err := cfg.Server.LogLevel.UnmarshalText([]byte(v)); err != nil {
+			return errors.Wrapf(err, "invalid %s", envLogLevel)
+		}
+	}
+	envString(envBaseURL, &cfg.Godoc.BaseURL)
+	envString(envProxy, &cfg.Godoc.Proxy)
+	envString(envUserAgent, &cfg.Godoc.UserAgent)
+	envString(envBackend, &cfg.Godoc.Backend)
+	envString(envVulnDB, &cfg.Godoc.VulnDB)
+
+	if err := envDuration(envToolTimeout, &cfg.Server.ToolTimeout); err != nil {
+		return err
+	}
+	if err := envDuration(envTimeout, &cfg.Godoc.Timeout); err != nil {
+		return err
+	}
+	if err := envInt64(envCacheNumCounters, &cfg.Godoc.Cache.NumCounters); err != nil {
+		return err
+	}
+	if err := envInt64(envCacheMaxCost, &cfg.Godoc.Cache.MaxCost); err != nil {
+		return err
+	}
+	if err := envInt64(envCacheBufferItems, &cfg.Godoc.Cache.BufferItems); err != nil {
+		return err
+	}
+	if err := envDuration(envCacheTTL, &cfg.Godoc.Cache.TTL); err != nil {
+		return err
+	}
+	if err := envDuration(envCacheMaxTTL, &cfg.Godoc.Cache.MaxTTL); err != nil {
+		return err
+	}
+	return nil
+}
+
+func envString(key string, dst *string) {
+	if v, ok := os.LookupEnv(key); ok {
+		*dst = v
+	}
+}
+
+func envDuration(key string, dst *time.Duration) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s", key)
+	}
+	*dst = d
+	return nil
+}
+
+func envInt64(key string, dst *int64) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s", key)
+	}
+	*dst = i
+	return nil
+}
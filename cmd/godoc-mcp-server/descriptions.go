@@ -0,0 +1,85 @@
+package main
+
+import (
+	_ "embed"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/tool"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed descriptions/default.yaml
+var defaultDescriptions []byte
+
+// descriptions 工具名到工具描述的映射
+type descriptions map[string]tool.Description
+
+func (d descriptions) merge(other descriptions) {
+	for name, desc := range other {
+		d[name] = d[name].Merge(desc)
+	}
+}
+
+func (d descriptions) get(name string) tool.Description {
+	return d[name]
+}
+
+// loadDescriptions 先加载内置的描述，然后用 path 中的描述覆盖
+// path 可以是单个 yaml/json 文件，也可以是目录，目录下的 yaml/json 文件会按文件名顺序依次覆盖
+func loadDescriptions(path string) (descriptions, error) {
+	descs := descriptions{}
+	if err := yaml.Unmarshal(defaultDescriptions, &descs); err != nil {
+		return nil, errors.Wrap(err, "parse default descriptions failed")
+	}
+	if path == "" {
+		return descs, nil
+	}
+
+	files, err := descriptionFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		override := descriptions{}
+		if err := yaml.Unmarshal(b, &override); err != nil {
+			return nil, errors.Wrapf(err, "parse descriptions file %s failed", file)
+		}
+		descs.merge(override)
+	}
+	return descs, nil
+}
+
+func descriptionFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// runHTTP 通过 streamable http 提供服务
+// 所有 session 共用同一个 server，godoc 的缓存是包级别的，所以各个 session 之间也是共享的
+func runHTTP(ctx context.Context, s *mcp.Server, addr string) error {
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return s
+	}, nil)
+
+	return serveHTTP(ctx, addr, handler)
+}
+
+// runSSE 通过旧版的 http+sse 提供服务，给只支持 2024-11-05 协议的客户端用
+// 每个 GET 请求会建立一个 session，POST 到对应的 endpoint 发送消息，GET 断开时 session 关闭
+func runSSE(ctx context.Context, s *mcp.Server, addr string) error {
+	handler := mcp.NewSSEHandler(func(*http.Request) *mcp.Server {
+		return s
+	}, nil)
+
+	return serveHTTP(ctx, addr, handler)
+}
+
+// serveHTTP 监听 addr 直到 ctx 结束，然后优雅关闭
+// 请求的 context 继承自 ctx，这样 sse 之类挂起的长连接在退出时也会结束，不会卡住 Shutdown
+func serveHTTP(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "server", name, "addr", addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -2,16 +2,64 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+)
+
+var (
+	configPath = flag.String("config", "", "path to the config file (yaml or json), can also be set by "+envConfig)
+	transport  = flag.String("transport", "", "transport to serve on: stdio, http or sse, overrides the config file")
+	addr       = flag.String("addr", "", "listen address when transport is http or sse, overrides the config file")
 )
 
 func main() {
-	s := initServer()
+	flag.Parse()
 
-	err := s.Run(context.Background(), &mcp.StdioTransport{})
+	cfg, err := loadConfig(*configPath)
 	if err != nil {
+		log.Fatal("load config failed. err:", err)
+	}
+	if *transport != "" {
+		cfg.Server.Transport = *transport
+	}
+	if *addr != "" {
+		cfg.Server.Addr = *addr
+	}
+	godoc.SetConfig(cfg.Godoc)
+	// stdout 在 stdio 模式下用来传输协议，日志只能写到 stderr
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: cfg.Server.LogLevel})))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	descs, err := loadDescriptions(cfg.Server.Descriptions)
+	if err != nil {
+		log.Fatal("load tool descriptions failed. err:", err)
+	}
+	s, err := initServer(cfg.Server, descs)
+	if err != nil {
+		log.Fatal("init server failed. err:", err)
+	}
+
+	switch cfg.Server.Transport {
+	case "stdio":
+		err = s.Run(ctx, &mcp.StdioTransport{})
+	case "http":
+		err = runHTTP(ctx, s, cfg.Server.Addr)
+	case "sse":
+		err = runSSE(ctx, s, cfg.Server.Addr)
+	default:
+		log.Fatalf("unknown transport %q, should be one of stdio, http, sse", cfg.Server.Transport)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
 		log.Fatal("unknown err, will exit. err:", err)
 	}
 }
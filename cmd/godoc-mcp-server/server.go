@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yikakia/godoc-mcp-server/pkg/prompt"
+	"github.com/yikakia/godoc-mcp-server/pkg/resource"
 	"github.com/yikakia/godoc-mcp-server/pkg/tool"
 )
 
@@ -10,27 +15,100 @@ var (
 	version = "v1.0.3"
 )
 
-func initServer() *mcp.Server {
+const (
+	getPackageInfoName = "getPackageInfo"
+	searchPackagesName = "searchPackages"
+	listVersionsName   = "listVersions"
+	getImportsName     = "getImports"
+	getImportedByName  = "getImportedBy"
+	getLicensesName    = "getLicenses"
+	getVulnsName       = "getVulnerabilities"
+)
+
+func initServer(cfg serverConfig, descs descriptions) (*mcp.Server, error) {
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    name,
 		Version: version,
-	}, nil)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Description: "provide a golang package name,get package consts,types,functions,variables," +
-			"subpackages and how to use it. If return is null then means cannot find the package by the given name",
-		Name: "getPackageInfo",
-	}, tool.GetPkgInfoTool())
-
-	mcp.AddTool(server, &mcp.Tool{
-		Description: "provide a query, search related golang packages from pkg.go.dev include " +
-			"name, path, synopsis, go doc url, imported by how many packages, subpackages in this package " +
-			"the path is the package full name. if want to use getPackageInfo. llm should pass the path as " +
-			"packageName to getPackageInfo. If return is null then means cannot find the package by the given name." +
-			"If user provide name like github.com/yikakia/cachalot looks like a repo then should use getPackageInfo to " +
-			"get the info of package directly.",
-		Name: "searchPackages",
-	}, tool.GetSearchTool())
-
-	return server
+	}, &mcp.ServerOptions{
+		InitializedHandler: onSessionInitialized,
+		CompletionHandler:  complete,
+	})
+
+	getPackageInfo, err := tool.NewTool[tool.GetPkgInfoParams](getPackageInfoName, descs.get(getPackageInfoName))
+	if err != nil {
+		return nil, err
+	}
+	mcp.AddTool(server, getPackageInfo, tool.WithTimeout(tool.GetPkgInfoTool(), cfg.toolTimeout(getPackageInfoName)))
+
+	searchPackages, err := tool.NewTool[tool.SearchParams](searchPackagesName, descs.get(searchPackagesName))
+	if err != nil {
+		return nil, err
+	}
+	mcp.AddTool(server, searchPackages, tool.WithTimeout(tool.GetSearchTool(), cfg.toolTimeout(searchPackagesName)))
+
+	listVersions, err := tool.NewTool[tool.ListVersionsParams](listVersionsName, descs.get(listVersionsName))
+	if err != nil {
+		return nil, err
+	}
+	mcp.AddTool(server, listVersions, tool.WithTimeout(tool.GetListVersionsTool(), cfg.toolTimeout(listVersionsName)))
+
+	getImports, err := tool.NewTool[tool.ImportsParams](getImportsName, descs.get(getImportsName))
+	if err != nil {
+		return nil, err
+	}
+	mcp.AddTool(server, getImports, tool.WithTimeout(tool.GetImportsTool(), cfg.toolTimeout(getImportsName)))
+
+	getImportedBy, err := tool.NewTool[tool.ImportsParams](getImportedByName, descs.get(getImportedByName))
+	if err != nil {
+		return nil, err
+	}
+	mcp.AddTool(server, getImportedBy, tool.WithTimeout(tool.GetImportedByTool(), cfg.toolTimeout(getImportedByName)))
+
+	getLicenses, err := tool.NewTool[tool.GetLicensesParams](getLicensesName, descs.get(getLicensesName))
+	if err != nil {
+		return nil, err
+	}
+	mcp.AddTool(server, getLicenses, tool.WithTimeout(tool.GetLicensesTool(), cfg.toolTimeout(getLicensesName)))
+
+	getVulns, err := tool.NewTool[tool.GetVulnsParams](getVulnsName, descs.get(getVulnsName))
+	if err != nil {
+		return nil, err
+	}
+	mcp.AddTool(server, getVulns, tool.WithTimeout(tool.GetVulnsTool(), cfg.toolTimeout(getVulnsName)))
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "packageDocument",
+		Title:       "Go package documentation",
+		Description: "the latest documentation of a golang package from pkg.go.dev rendered as markdown, importPath is the full import path of the package",
+		MIMEType:    "text/markdown",
+		URITemplate: resource.PkgDocURITemplate,
+	}, resource.GetPkgDocResource())
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "packageDocumentAtVersion",
+		Title:       "Go package documentation at version",
+		Description: "the documentation of a golang package at the given version from pkg.go.dev rendered as markdown, importPath is the full import path of the package",
+		MIMEType:    "text/markdown",
+		URITemplate: resource.PkgDocVersionURITemplate,
+	}, resource.GetPkgDocResource())
+
+	server.AddPrompt(prompt.ExplainPackage())
+	server.AddPrompt(prompt.ChooseLibrary())
+	server.AddPrompt(prompt.MigrateVersions())
+
+	return server, nil
+}
+
+// onSessionInitialized 记录 session 的生命周期，方便在 http/sse 模式下排查多个客户端共用一个实例的情况
+// sse 和 stdio 的 session 没有 id，所以同时记录客户端信息
+func onSessionInitialized(ctx context.Context, req *mcp.InitializedRequest) {
+	ss := req.Session
+	client := "unknown"
+	if p := ss.InitializeParams(); p != nil && p.ClientInfo != nil {
+		client = p.ClientInfo.Name + " " + p.ClientInfo.Version
+	}
+	slog.InfoContext(ctx, "session initialized", "session", ss.ID(), "client", client)
+	go func() {
+		_ = ss.Wait()
+		slog.Info("session closed", "session", ss.ID(), "client", client)
+	}()
 }
@@ -1,14 +1,100 @@
 package godoc
 
 import (
-	"sync"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
 )
 
-var client = sync.OnceValue(resty.New)
+// ClientConfig http client 的配置
+type ClientConfig struct {
+	// Timeout 请求的超时时间，0 表示不超时
+	Timeout time.Duration
+	// Proxy 请求使用的代理，为空则使用环境变量里的代理
+	Proxy string
+	// UserAgent 为空则使用 resty 的默认值
+	UserAgent string
+	// Headers 每个请求都带上的 header，值里的 $VAR 会用环境变量展开
+	Headers map[string]string
+	// BearerToken 不为空时设置 Authorization: Bearer，$VAR 会用环境变量展开
+	BearerToken string
+	TLS         TLSConfig
+}
+
+func newClient(cfg ClientConfig) (*resty.Client, error) {
+	c := resty.New().SetTimeout(cfg.Timeout)
+	if cfg.Proxy != "" {
+		c.SetProxy(cfg.Proxy)
+	}
+	if cfg.UserAgent != "" {
+		c.SetHeader("User-Agent", cfg.UserAgent)
+	}
+	for k, v := range cfg.Headers {
+		c.SetHeader(k, os.ExpandEnv(v))
+	}
+	if cfg.BearerToken != "" {
+		c.SetAuthToken(os.ExpandEnv(cfg.BearerToken))
+	}
+	tlsConfig, err := cfg.TLS.build()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		c.SetTLSClientConfig(tlsConfig)
+	}
+	return c, nil
+}
+
+// build 没有任何配置时返回 nil，使用默认的 TLS 配置
+func (t TLSConfig) build() (*tls.Config, error) {
+	if t == (TLSConfig{}) {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificate found in %s", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
 
-// 这个要考虑支持设置
-func baseURL() string {
-	return "https://pkg.go.dev"
+// logResponse 记录上游的响应，非 2xx 的响应记为 warn
+func logResponse(ctx context.Context, resp *resty.Response) {
+	l := logging.FromContext(ctx)
+	attrs := []any{
+		"url", resp.Request.URL,
+		"status", resp.StatusCode(),
+		"elapsed", resp.Time(),
+	}
+	if !resp.IsSuccess() {
+		l.WarnContext(ctx, "upstream responded with non-2xx status", attrs...)
+		return
+	}
+	l.DebugContext(ctx, "fetched from upstream", attrs...)
 }
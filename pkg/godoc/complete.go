@@ -0,0 +1,109 @@
+package godoc
+
+import (
+	_ "embed"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// stdlib.txt 由 go list std 生成，去掉了 internal 和 vendor 的包
+//
+//go:embed stdlib.txt
+var stdlibList string
+
+var stdPackages = sync.OnceValue(func() []string {
+	return strings.Fields(stdlibList)
+})
+
+// IsStdPackage 判断是否是标准库的包
+func IsStdPackage(importPath string) bool {
+	_, ok := slices.BinarySearch(stdPackages(), importPath)
+	return ok
+}
+
+// maxRecentPaths 最多记录多少个最近见过的 import path
+const maxRecentPaths = 4096
+
+// recentPaths 记录搜索结果和文档里出现过的 import path，用于补全
+// 超过上限后淘汰最早加入的
+type recentPaths struct {
+	mu    sync.Mutex
+	order []string
+	set   map[string]struct{}
+}
+
+var recent = &recentPaths{set: make(map[string]struct{})}
+
+func (r *recentPaths) add(paths ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, ok := r.set[p]; ok {
+			continue
+		}
+		r.set[p] = struct{}{}
+		r.order = append(r.order, p)
+	}
+	if over := len(r.order) - maxRecentPaths; over > 0 {
+		for _, p := range r.order[:over] {
+			delete(r.set, p)
+		}
+		r.order = slices.Clone(r.order[over:])
+	}
+}
+
+func (r *recentPaths) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.order)
+}
+
+func recordSearchResult(result *SearchResult) {
+	for _, p := range result.Packages {
+		recent.add(p.Path)
+		recent.add(p.SubPackages...)
+	}
+	for _, s := range result.Symbols {
+		recent.add(s.PackagePath)
+	}
+}
+
+func recordPackageDocument(pkgName string, doc *PackageDocument) {
+	// 带版本号的时候只记录 import path
+	pkgName, _ = splitVersion(pkgName)
+	recent.add(pkgName)
+	for _, sub := range doc.SubPackages {
+		recent.add(pkgName + "/" + sub.Name)
+	}
+}
+
+// CompleteImportPath 返回以 prefix 开头的 import path，来源是最近的搜索结果、
+// 已经获取过的文档的子包以及标准库。结果按字典序排列，最多 limit 个，同时返回匹配的总数
+func CompleteImportPath(prefix string, limit int) ([]string, int) {
+	seen := make(map[string]struct{})
+	var matched []string
+	for _, candidates := range [][]string{recent.list(), stdPackages()} {
+		for _, p := range candidates {
+			if !strings.HasPrefix(p, prefix) {
+				continue
+			}
+			if _, ok := seen[p]; ok {
+				continue
+			}
+			seen[p] = struct{}{}
+			matched = append(matched, p)
+		}
+	}
+	slices.Sort(matched)
+
+	total := len(matched)
+	if limit > 0 && total > limit {
+		matched = matched[:limit]
+	}
+	return matched, total
+}
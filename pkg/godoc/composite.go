@@ -0,0 +1,122 @@
+package godoc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+	"go.uber.org/multierr"
+	"golang.org/x/mod/module"
+)
+
+// Route 把匹配 Patterns 的 import path 交给 Providers 处理
+type Route struct {
+	// Patterns 逗号分隔的 glob，语法和 GOPRIVATE 一样，匹配 import path 的前缀，例如 git.corp.example/*
+	Patterns string
+	// Providers 按顺序尝试，前一个失败时使用下一个
+	Providers []Provider
+}
+
+// CompositeProvider 按 import path 选择 Provider，并按顺序回退
+type CompositeProvider struct {
+	routes   []Route
+	fallback []Provider
+}
+
+// NewCompositeProvider 匹配 routes 中第一个符合的 Route，都不匹配时按顺序使用 fallback
+func NewCompositeProvider(routes []Route, fallback ...Provider) *CompositeProvider {
+	return &CompositeProvider{
+		routes:   routes,
+		fallback: fallback,
+	}
+}
+
+func (c *CompositeProvider) Name() string {
+	return "composite"
+}
+
+// providersFor 返回处理 importPath 的 Provider
+func (c *CompositeProvider) providersFor(importPath string) []Provider {
+	importPath, _ = splitVersion(importPath)
+	for _, r := range c.routes {
+		if module.MatchPrefixPatterns(r.Patterns, importPath) {
+			return r.Providers
+		}
+	}
+	return c.fallback
+}
+
+func (c *CompositeProvider) Search(ctx context.Context, req SearchRequest) (*SearchResult, error) {
+	// 查询像 import path 时也按路由选择，例如直接搜索内部的仓库
+	return tryProviders(ctx, c.providersFor(strings.TrimSpace(req.Query)), func(p Provider) (*SearchResult, error) {
+		return p.Search(ctx, req)
+	})
+}
+
+func (c *CompositeProvider) GetPackageDocument(ctx context.Context, req GetPackageRequest) (*PackageDocument, error) {
+	return tryProviders(ctx, c.providersFor(req.PackageName), func(p Provider) (*PackageDocument, error) {
+		return p.GetPackageDocument(ctx, req)
+	})
+}
+
+func (c *CompositeProvider) ListVersions(ctx context.Context, importPath string) (*VersionList, error) {
+	return tryProviders(ctx, c.providersFor(importPath), func(p Provider) (*VersionList, error) {
+		return p.ListVersions(ctx, importPath)
+	})
+}
+
+func (c *CompositeProvider) GetLicenses(ctx context.Context, importPath string) (*LicenseList, error) {
+	return tryProviders(ctx, c.providersFor(importPath), func(p Provider) (*LicenseList, error) {
+		return p.GetLicenses(ctx, importPath)
+	})
+}
+
+func (c *CompositeProvider) GetImports(ctx context.Context, importPath string) ([]ImportRef, error) {
+	refs, err := tryProviders(ctx, c.providersFor(importPath), func(p Provider) (*[]ImportRef, error) {
+		refs, err := p.GetImports(ctx, importPath)
+		return &refs, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *refs, nil
+}
+
+func (c *CompositeProvider) GetImportedBy(ctx context.Context, importPath string) ([]ImportRef, error) {
+	refs, err := tryProviders(ctx, c.providersFor(importPath), func(p Provider) (*[]ImportRef, error) {
+		refs, err := p.GetImportedBy(ctx, importPath)
+		return &refs, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *refs, nil
+}
+
+// tryProviders 按顺序调用，直到有一个成功。ctx 结束时不再尝试
+func tryProviders[T any](ctx context.Context, providers []Provider, fn func(p Provider) (*T, error)) (*T, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("no provider configured")
+	}
+	var err error
+	for i, p := range providers {
+		result, _err := fn(p)
+		if _err == nil {
+			return result, nil
+		}
+		if !errors.Is(_err, ErrNotSupported) {
+			err = multierr.Append(err, errors.WithMessage(_err, p.Name()))
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if i < len(providers)-1 {
+			logging.FromContext(ctx).DebugContext(ctx, "provider failed, try next", "provider", p.Name(), "err", _err.Error())
+		}
+	}
+	if err == nil {
+		return nil, ErrNotSupported
+	}
+	return nil, err
+}
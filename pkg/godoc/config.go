@@ -0,0 +1,148 @@
+package godoc
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// BackendPkgsite 从 pkg.go.dev 获取文档
+	BackendPkgsite = "pkgsite"
+	// BackendLocal 从本地的 GOROOT, GOMODCACHE, GOPATH 中解析源码生成文档，可以离线使用
+	BackendLocal = "local"
+	// BackendAuto 优先从 pkg.go.dev 获取，失败时使用本地的源码
+	BackendAuto = "auto"
+)
+
+// Config 是 godoc 包的配置，需要在第一次调用 Search/GetPackageDocument 之前通过 SetConfig 设置
+type Config struct {
+	// BaseURL pkg.go.dev 或者兼容的 pkgsite 的地址
+	BaseURL string `yaml:"baseURL"`
+	// Timeout 请求上游的超时时间，0s 表示不超时
+	Timeout time.Duration `yaml:"timeout"`
+	// Proxy 请求上游使用的代理，为空则使用环境变量里的代理
+	Proxy string `yaml:"proxy"`
+	// UserAgent 请求上游时的 User-Agent，为空则使用 resty 的默认值
+	UserAgent string `yaml:"userAgent"`
+	// Headers 请求 BaseURL 时附加的 header，值里的 $VAR 会用环境变量展开
+	Headers map[string]string `yaml:"headers"`
+	// BearerToken 请求 BaseURL 时的 Authorization: Bearer，$VAR 会用环境变量展开
+	BearerToken string    `yaml:"bearerToken"`
+	TLS         TLSConfig `yaml:"tls"`
+	// Backend 文档的来源，pkgsite, local 或 auto
+	Backend string `yaml:"backend"`
+	// Pkgsites 自建的 pkgsite，匹配 Patterns 的 import path 使用对应的 pkgsite，都不匹配时使用 BaseURL
+	// backend 为 local 时不使用
+	Pkgsites []PkgsiteSource `yaml:"pkgsites"`
+	// VulnDB vuln.go.dev 格式的漏洞数据库目录，为空时不查询漏洞
+	VulnDB string `yaml:"vulnDB"`
+
+	Cache   CacheConfig   `yaml:"cache"`
+	Local   LocalConfig   `yaml:"local"`
+	Private PrivateConfig `yaml:"private"`
+}
+
+type CacheConfig struct {
+	// ristretto 的参数，具体含义见 ristretto.Config
+	NumCounters int64 `yaml:"numCounters"`
+	MaxCost     int64 `yaml:"maxCost"`
+	BufferItems int64 `yaml:"bufferItems"`
+	// TTL 逻辑过期时间，过期后仍然返回旧数据，同时在后台回源刷新。默认 10 分钟，显式设置为 0s 表示永不过期
+	TTL time.Duration `yaml:"ttl"`
+	// MaxTTL 物理过期时间，过期后数据会被删除，下次请求会同步回源。默认 1 小时，显式设置为 0s 表示永不过期
+	MaxTTL time.Duration `yaml:"maxTTL"`
+}
+
+// PkgsiteSource 自建的 pkgsite
+// Timeout, Proxy, UserAgent 为空时使用 Config 中的值，Headers, BearerToken, TLS 不会继承，避免凭证发给其他站点
+type PkgsiteSource struct {
+	// Name 用于日志和缓存 key，为空时使用 BaseURL 的 host
+	Name    string `yaml:"name"`
+	BaseURL string `yaml:"baseURL"`
+	// Patterns 逗号分隔的 glob，语法和 GOPRIVATE 一样，例如 git.corp.example/*
+	Patterns    string            `yaml:"patterns"`
+	Timeout     time.Duration     `yaml:"timeout"`
+	Proxy       string            `yaml:"proxy"`
+	UserAgent   string            `yaml:"userAgent"`
+	Headers     map[string]string `yaml:"headers"`
+	BearerToken string            `yaml:"bearerToken"`
+	TLS         TLSConfig         `yaml:"tls"`
+}
+
+// TLSConfig 请求上游的 TLS 配置，例如自签名的证书或者需要客户端证书的 pkgsite
+type TLSConfig struct {
+	// CAFile 额外信任的 CA 证书，PEM 格式
+	CAFile string `yaml:"caFile"`
+	// CertFile 和 KeyFile 是客户端证书，用于 mTLS
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+	// ServerName 为空时使用请求的 host
+	ServerName         string `yaml:"serverName"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+}
+
+// PrivateConfig 私有 import path 的 glob，语法和 go 命令的同名环境变量一样，为空时使用 go env 的值
+// 匹配任意一个的 import path 不会发给 BaseURL，只会使用 Pkgsites 中匹配的 pkgsite 或者本地的源码
+type PrivateConfig struct {
+	GOPRIVATE string `yaml:"goprivate"`
+	GONOPROXY string `yaml:"gonoproxy"`
+	GONOSUMDB string `yaml:"gonosumdb"`
+}
+
+// LocalConfig 本地文档查找的目录，为空时使用 go env 的值
+type LocalConfig struct {
+	GOROOT     string `yaml:"goroot"`
+	GOMODCACHE string `yaml:"gomodcache"`
+	GOPATH     string `yaml:"gopath"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BaseURL: "https://pkg.go.dev",
+		Timeout: 30 * time.Second,
+		Backend: BackendPkgsite,
+		Cache: CacheConfig{
+			NumCounters: 1 << 10,
+			MaxCost:     1 << 20,
+			BufferItems: 64,
+			TTL:         10 * time.Minute,
+			MaxTTL:      time.Hour,
+		},
+	}
+}
+
+var (
+	configMu sync.RWMutex
+	config   = DefaultConfig()
+)
+
+// SetConfig 设置配置，缓存和 http client 是懒加载的，所以需要在第一次使用前调用
+// 零值字段会使用 DefaultConfig 中的值，Cache.TTL 和 Cache.MaxTTL 除外，它们的 0 表示永不过期
+func SetConfig(cfg Config) {
+	def := DefaultConfig()
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = def.BaseURL
+	}
+	if cfg.Backend == "" {
+		cfg.Backend = def.Backend
+	}
+	if cfg.Cache.NumCounters <= 0 {
+		cfg.Cache.NumCounters = def.Cache.NumCounters
+	}
+	if cfg.Cache.MaxCost <= 0 {
+		cfg.Cache.MaxCost = def.Cache.MaxCost
+	}
+	if cfg.Cache.BufferItems <= 0 {
+		cfg.Cache.BufferItems = def.Cache.BufferItems
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+	config = cfg
+}
+
+func getConfig() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return config
+}
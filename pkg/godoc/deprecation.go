@@ -0,0 +1,85 @@
+package godoc
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Deprecation 来自注释中的 Deprecated: 段落，或者 go.mod 中 module 的 // Deprecated: 注释
+type Deprecation struct {
+	// Message Deprecated: 后面的说明
+	Message string
+	// Replacement 说明中提到的替代，例如 io.ReadAll，识别不了时为空
+	Replacement string `json:",omitempty"`
+}
+
+// deprecatedParagraph 按照约定 Deprecated: 在段落的开头
+var deprecatedParagraph = regexp.MustCompile(`(?:^|\n\n)Deprecated:\s*`)
+
+// replacementPatterns 按优先级排列，前面的模式匹配到时不再尝试后面的
+var replacementPatterns = []*regexp.Regexp{
+	// doc comment 中的链接，例如 As of Go 1.16, this function simply calls [io.ReadAll].
+	regexp.MustCompile(`\[([A-Za-z_][\w./*]*)\]`),
+	// Use X instead
+	regexp.MustCompile(`(?i)\b(?:use|using)\s+(?:the\s+)?([A-Za-z_][\w./*]*(?:\(\))?)\s+instead\b`),
+	// Use X, replaced by X, in favor of X
+	regexp.MustCompile(`(?i)\b(?:use|using|replaced by|superseded by|in favou?r of|prefer|switch to|migrate to)\s+(?:the\s+)?([A-Za-z_][\w./*]*(?:\(\))?)`),
+	regexp.MustCompile(`(?i)\bsimply calls\s+([A-Za-z_][\w./*]*)`),
+}
+
+// replacementStopWords 不是替代的词，例如 do not use this 中的 this
+var replacementStopWords = map[string]bool{
+	"this": true, "that": true, "these": true, "those": true, "it": true, "them": true,
+	"of": true, "a": true, "an": true, "any": true, "instead": true,
+}
+
+// parseDeprecation 没有 Deprecated: 段落时返回 nil
+func parseDeprecation(comment string) *Deprecation {
+	loc := deprecatedParagraph.FindStringIndex(comment)
+	if loc == nil {
+		return nil
+	}
+	message := comment[loc[1]:]
+	if i := strings.Index(message, "\n\n"); i >= 0 {
+		message = message[:i]
+	}
+	message = strings.Join(strings.Fields(message), " ")
+	return newDeprecation(message)
+}
+
+func newDeprecation(message string) *Deprecation {
+	d := &Deprecation{Message: message}
+	for _, p := range replacementPatterns {
+		for _, m := range p.FindAllStringSubmatch(message, -1) {
+			replacement := strings.TrimRight(m[1], ".")
+			if !replacementStopWords[strings.ToLower(replacement)] {
+				d.Replacement = replacement
+				return d
+			}
+		}
+	}
+	return d
+}
+
+// markDeprecations 根据注释填充各个部分的 Deprecated
+func markDeprecations(d *PackageDocument) {
+	for i := range d.Consts {
+		d.Consts[i].Deprecated = parseDeprecation(d.Consts[i].Comment)
+	}
+	for i := range d.Variables {
+		d.Variables[i].Deprecated = parseDeprecation(d.Variables[i].Comment)
+	}
+	for i := range d.Functions {
+		d.Functions[i].Deprecated = parseDeprecation(d.Functions[i].Comment)
+	}
+	for i := range d.Types {
+		t := &d.Types[i]
+		t.Deprecated = parseDeprecation(t.Comment)
+		for j := range t.TypeFunctions {
+			t.TypeFunctions[j].Deprecated = parseDeprecation(t.TypeFunctions[j].Comment)
+		}
+		for j := range t.TypeMethods {
+			t.TypeMethods[j].Deprecated = parseDeprecation(t.TypeMethods[j].Comment)
+		}
+	}
+}
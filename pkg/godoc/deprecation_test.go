@@ -0,0 +1,76 @@
+package godoc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDeprecation(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		want    *Deprecation
+	}{
+		{
+			name:    "ioutil.ReadAll",
+			comment: "ReadAll reads from r until an error or EOF and returns the data it read.\n\nDeprecated: As of Go 1.16, this function simply calls [io.ReadAll].",
+			want: &Deprecation{
+				Message:     "As of Go 1.16, this function simply calls [io.ReadAll].",
+				Replacement: "io.ReadAll",
+			},
+		},
+		{
+			name:    "strings.Title",
+			comment: "Title returns a copy of the string s with all Unicode letters that begin words\nmapped to their Unicode title case.\n\nDeprecated: The rule Title uses for word boundaries does not handle Unicode\npunctuation properly. Use golang.org/x/text/cases instead.",
+			want: &Deprecation{
+				Message:     "The rule Title uses for word boundaries does not handle Unicode punctuation properly. Use golang.org/x/text/cases instead.",
+				Replacement: "golang.org/x/text/cases",
+			},
+		},
+		{
+			name:    "paragraph after deprecation",
+			comment: "Deprecated: Use unsafe.Slice or unsafe.SliceData instead.\n\nIn new code, use unsafe.Slice.",
+			want: &Deprecation{
+				Message:     "Use unsafe.Slice or unsafe.SliceData instead.",
+				Replacement: "unsafe.Slice",
+			},
+		},
+		{
+			name:    "not at paragraph start",
+			comment: "Foo is no longer needed. Deprecated: use Bar.",
+		},
+		{
+			name:    "no deprecation",
+			comment: "ReadAll reads from r until an error or EOF.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseDeprecation(tt.comment))
+		})
+	}
+}
+
+func TestNewDeprecation(t *testing.T) {
+	tests := []struct {
+		message     string
+		replacement string
+	}{
+		{"As of Go 1.16, this function simply calls io.ReadAll.", "io.ReadAll"},
+		{"As of Go 1.16, this function simply calls [io.ReadAll].", "io.ReadAll"},
+		{"do not use this, use Foo instead", "Foo"},
+		{"Use the [go/types] package instead.", "go/types"},
+		{"replaced by NewClient().", "NewClient()"},
+		{"in favor of github.com/example/v2", "github.com/example/v2"},
+		{"this package is frozen, use of it is discouraged.", ""},
+		{"HTTP/1.x pipelining is not robust.", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			d := newDeprecation(tt.message)
+			assert.Equal(t, tt.message, d.Message)
+			assert.Equal(t, tt.replacement, d.Replacement)
+		})
+	}
+}
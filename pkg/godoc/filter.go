@@ -0,0 +1,120 @@
+package godoc
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// SearchFilter 过滤搜索结果，零值不过滤
+// 只过滤当前页，所以一页的结果可能少于 Limit。搜索符号时 StableOnly 和 Licenses 不生效
+type SearchFilter struct {
+	ExcludeStd bool
+	// ExcludeInternal 排除 internal 和 examples 目录下的包
+	ExcludeInternal bool
+	// StableOnly 只保留最新版本是 v1 及以上正式版本的包，版本未知的包也会被排除
+	StableOnly    bool
+	MinImportedBy int
+	// Licenses 允许的 license 类型，例如 MIT，不区分大小写。为空时不限制，没有 license 信息的包会被排除
+	Licenses []string
+}
+
+func (f SearchFilter) keepPath(importPath string, importedBy int) bool {
+	if f.ExcludeStd && isStdImportPath(importPath) {
+		return false
+	}
+	if f.ExcludeInternal && isInternalPath(importPath) {
+		return false
+	}
+	return importedBy >= f.MinImportedBy
+}
+
+func (f SearchFilter) keepPackage(p *SearchPackageInfo) bool {
+	if !f.keepPath(p.Path, p.ImportedBy) {
+		return false
+	}
+	if f.StableOnly && !isStableVersion(p.Version) {
+		return false
+	}
+	if len(f.Licenses) > 0 && !slices.ContainsFunc(p.Licenses, func(l string) bool {
+		return slices.ContainsFunc(f.Licenses, func(allowed string) bool {
+			return strings.EqualFold(strings.TrimSpace(allowed), l)
+		})
+	}) {
+		return false
+	}
+	return true
+}
+
+// isInternalPath 路径中有 internal, examples 或 _examples 目录
+func isInternalPath(importPath string) bool {
+	for _, elem := range strings.Split(importPath, "/") {
+		switch elem {
+		case "internal", "examples", "_examples":
+			return true
+		}
+	}
+	return false
+}
+
+// isStableVersion 标准库的版本总是稳定的
+func isStableVersion(version string) bool {
+	if strings.HasPrefix(version, "go1") {
+		return true
+	}
+	return semver.IsValid(version) && semver.Major(version) != "v0" && semver.Prerelease(version) == ""
+}
+
+// filterSearchResult 返回被过滤掉的数量
+func filterSearchResult(result *SearchResult, f SearchFilter) int {
+	n := len(result.Packages) + len(result.Symbols)
+	result.Packages = slices.DeleteFunc(result.Packages, func(p *SearchPackageInfo) bool {
+		return !f.keepPackage(p)
+	})
+	result.Symbols = slices.DeleteFunc(result.Symbols, func(s *SymbolSearchResult) bool {
+		return !f.keepPath(s.PackagePath, s.ImportedBy)
+	})
+	return n - len(result.Packages) - len(result.Symbols)
+}
+
+// 重新排序时各项的权重
+const (
+	rankWeightOrder      = 0.5
+	rankWeightImportedBy = 0.3
+	rankWeightRecency    = 0.2
+	// rankRecencyHalfLife 发布时间的分数每过这么久减半
+	rankRecencyHalfLife = 2 * 365 * 24 * time.Hour
+)
+
+// rerankSearchResult 结合 pkg.go.dev 的顺序、ImportedBy 和发布时间重新排序当前页的包
+// ImportedBy 取对数，避免几个特别流行的包压过其他所有因素
+func rerankSearchResult(result *SearchResult, now time.Time) {
+	n := len(result.Packages)
+	if n < 2 {
+		return
+	}
+	maxImportedBy := 0
+	for _, p := range result.Packages {
+		maxImportedBy = max(maxImportedBy, p.ImportedBy)
+	}
+
+	scores := make(map[*SearchPackageInfo]float64, n)
+	for i, p := range result.Packages {
+		score := rankWeightOrder * (1 - float64(i)/float64(n))
+		if maxImportedBy > 0 {
+			score += rankWeightImportedBy * math.Log1p(float64(p.ImportedBy)) / math.Log1p(float64(maxImportedBy))
+		}
+		if !p.Published.IsZero() {
+			age := max(now.Sub(p.Published), 0)
+			score += rankWeightRecency * math.Exp2(-float64(age)/float64(rankRecencyHalfLife))
+		}
+		scores[p] = score
+	}
+	slices.SortStableFunc(result.Packages, func(a, b *SearchPackageInfo) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+}
@@ -0,0 +1,149 @@
+package godoc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStableVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"go1.24.1", true},
+		{"go1.25rc1", true},
+		{"v1.2.3", true},
+		{"v2.0.0+incompatible", true},
+		{"v0.9.1", false},
+		{"v1.0.0-rc.1", false},
+		{"v0.0.0-20240101000000-abcdefabcdef", false},
+		{"", false},
+		{"latest", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, isStableVersion(tt.version))
+		})
+	}
+}
+
+func TestKeepPackage(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter SearchFilter
+		pkg    SearchPackageInfo
+		want   bool
+	}{
+		{
+			name: "zero filter",
+			pkg:  SearchPackageInfo{Path: "example.com/internal/x"},
+			want: true,
+		},
+		{
+			name:   "exclude std",
+			filter: SearchFilter{ExcludeStd: true},
+			pkg:    SearchPackageInfo{Path: "net/http", Version: "go1.24.1"},
+		},
+		{
+			name:   "exclude internal",
+			filter: SearchFilter{ExcludeInternal: true},
+			pkg:    SearchPackageInfo{Path: "github.com/example/mod/_examples/basic"},
+		},
+		{
+			name:   "min imported by",
+			filter: SearchFilter{MinImportedBy: 10},
+			pkg:    SearchPackageInfo{Path: "example.com/mod", ImportedBy: 9},
+		},
+		{
+			name:   "stable std",
+			filter: SearchFilter{StableOnly: true},
+			pkg:    SearchPackageInfo{Path: "net/http", Version: "go1.24.1"},
+			want:   true,
+		},
+		{
+			name:   "stable v0",
+			filter: SearchFilter{StableOnly: true},
+			pkg:    SearchPackageInfo{Path: "example.com/mod", Version: "v0.9.1"},
+		},
+		{
+			name:   "stable prerelease",
+			filter: SearchFilter{StableOnly: true},
+			pkg:    SearchPackageInfo{Path: "example.com/mod", Version: "v1.0.0-beta.1"},
+		},
+		{
+			name:   "stable unknown version",
+			filter: SearchFilter{StableOnly: true},
+			pkg:    SearchPackageInfo{Path: "example.com/mod"},
+		},
+		{
+			name:   "allowed license",
+			filter: SearchFilter{Licenses: []string{" mit ", "Apache-2.0"}},
+			pkg:    SearchPackageInfo{Path: "example.com/mod", Licenses: []string{"BSD-3-Clause", "MIT"}},
+			want:   true,
+		},
+		{
+			name:   "other license",
+			filter: SearchFilter{Licenses: []string{"MIT"}},
+			pkg:    SearchPackageInfo{Path: "example.com/mod", Licenses: []string{"GPL-3.0"}},
+		},
+		{
+			name:   "no license with allowlist",
+			filter: SearchFilter{Licenses: []string{"MIT"}},
+			pkg:    SearchPackageInfo{Path: "example.com/mod"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.keepPackage(&tt.pkg))
+		})
+	}
+}
+
+func TestFilterSearchResult(t *testing.T) {
+	result := &SearchResult{
+		Packages: []*SearchPackageInfo{
+			{Path: "net/http", Version: "go1.24.1"},
+			{Path: "example.com/mod", Version: "v1.0.0"},
+		},
+		Symbols: []*SymbolSearchResult{
+			{PackagePath: "strings"},
+			{PackagePath: "example.com/mod/internal/x"},
+			{PackagePath: "example.com/mod"},
+		},
+	}
+	// 搜索符号时 StableOnly 不生效
+	n := filterSearchResult(result, SearchFilter{ExcludeStd: true, ExcludeInternal: true, StableOnly: true})
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []*SearchPackageInfo{{Path: "example.com/mod", Version: "v1.0.0"}}, result.Packages)
+	assert.Equal(t, []*SymbolSearchResult{{PackagePath: "example.com/mod"}}, result.Symbols)
+}
+
+func TestRerankSearchResult(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := &SearchResult{
+		Packages: []*SearchPackageInfo{
+			// 0.5 + 0.3*log(11)/log(1001) + 0.2/1024 ≈ 0.6
+			{Path: "example.com/first", ImportedBy: 10, Published: now.Add(-10 * rankRecencyHalfLife)},
+			// 0.5*3/4 + 0.3 + 0.2 ≈ 0.88
+			{Path: "example.com/popular", ImportedBy: 1000, Published: now},
+			// 0.5*2/4 = 0.25，没有发布时间
+			{Path: "example.com/unknown"},
+			// 发布时间在 now 之后按 now 算，0.5/4 + 0 + 0.2 ≈ 0.33
+			{Path: "example.com/future", Published: now.Add(time.Hour)},
+		},
+	}
+	rerankSearchResult(result, now)
+
+	var paths []string
+	for _, p := range result.Packages {
+		paths = append(paths, p.Path)
+	}
+	assert.Equal(t, []string{
+		"example.com/popular",
+		"example.com/first",
+		"example.com/future",
+		"example.com/unknown",
+	}, paths)
+}
@@ -0,0 +1,181 @@
+package godoc
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// defaultImportsLimit ImportsRequest.Limit 为 0 时每页的数量
+const defaultImportsLimit = 100
+
+// ImportRef 导入的或者导入了当前包的包
+type ImportRef struct {
+	Path string
+	// Module 包所在的 module，页面上没有时根据 import path 推测，标准库是 std
+	Module string
+}
+
+type ImportsRequest struct {
+	// PackageName 可以带版本，例如 github.com/pkg/errors@v0.9.1
+	PackageName string
+	// Page 从 1 开始，0 等同于 1
+	Page int
+	// Limit 每页的数量，0 时使用 defaultImportsLimit
+	Limit int
+}
+
+// ImportList 分页后的导入列表，按 module 分组
+type ImportList struct {
+	// Total 所有页的包的数量
+	Total int
+	// Std 标准库中的包，只有 imports 才有
+	Std     []string         `json:",omitempty"`
+	Modules []*ModuleImports `json:",omitempty"`
+	// NextPage 下一页的页码，0 表示没有下一页
+	NextPage int `json:",omitempty"`
+}
+
+type ModuleImports struct {
+	Module   string
+	Packages []string
+}
+
+// GetImports 列出包导入的包，标准库单独列出
+func GetImports(ctx context.Context, req ImportsRequest) (*ImportList, error) {
+	p, err := DefaultProvider()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := p.GetImports(ctx, req.PackageName)
+	if err != nil {
+		return nil, err
+	}
+	return newImportList(refs, req), nil
+}
+
+// GetImportedBy 列出导入了这个包的包
+func GetImportedBy(ctx context.Context, req ImportsRequest) (*ImportList, error) {
+	p, err := DefaultProvider()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := p.GetImportedBy(ctx, req.PackageName)
+	if err != nil {
+		return nil, err
+	}
+	return newImportList(refs, req), nil
+}
+
+// newImportList 分页并分组，refs 的顺序会被保留
+func newImportList(refs []ImportRef, req ImportsRequest) *ImportList {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultImportsLimit
+	}
+	page := max(req.Page, 1)
+
+	result := &ImportList{Total: len(refs)}
+	start := min((page-1)*limit, len(refs))
+	end := min(start+limit, len(refs))
+	if end < len(refs) {
+		result.NextPage = page + 1
+	}
+
+	modules := make(map[string]*ModuleImports)
+	for _, ref := range refs[start:end] {
+		if ref.Module == "std" {
+			result.Std = append(result.Std, ref.Path)
+			continue
+		}
+		m, ok := modules[ref.Module]
+		if !ok {
+			m = &ModuleImports{Module: ref.Module}
+			modules[ref.Module] = m
+			result.Modules = append(result.Modules, m)
+		}
+		m.Packages = append(m.Packages, ref.Path)
+	}
+	return result
+}
+
+// newImportRef 没有 module 时根据 import path 推测
+func newImportRef(importPath, module string) ImportRef {
+	if isStdImportPath(importPath) {
+		return ImportRef{Path: importPath, Module: "std"}
+	}
+	if module == "" {
+		module = guessModulePath(importPath)
+	}
+	return ImportRef{Path: importPath, Module: module}
+}
+
+// isStdImportPath 标准库的路径第一段没有 .，和 go 命令的规则一样
+func isStdImportPath(importPath string) bool {
+	if IsStdPackage(importPath) {
+		return true
+	}
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// modulePathElems 常见的代码托管站点的 module path 有几段
+var modulePathElems = map[string]int{
+	"github.com":        3,
+	"gitlab.com":        3,
+	"bitbucket.org":     3,
+	"codeberg.org":      3,
+	"gitee.com":         3,
+	"golang.org":        3,
+	"gopkg.in":          2,
+	"google.golang.org": 2,
+	"go.uber.org":       2,
+	"k8s.io":            2,
+	"sigs.k8s.io":       2,
+}
+
+var majorVersionElem = regexp.MustCompile(`^v([2-9]|[1-9][0-9]+)$`)
+
+// guessModulePath 只是推测，用于分组，例如 github.com/a/b/c 的 module 是 github.com/a/b
+// 不认识的站点取前三段，后面是 /vN 时带上主版本
+func guessModulePath(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	n, ok := modulePathElems[elems[0]]
+	if !ok {
+		n = 3
+	}
+	n = min(n, len(elems))
+	if n < len(elems) && majorVersionElem.MatchString(elems[n]) {
+		n++
+	}
+	return strings.Join(elems[:n], "/")
+}
+
+// extractImportRefs 解析 ?tab=imports 和 ?tab=importedby 页面中的包
+// 在 details 中的包使用 summary 作为 module，否则根据 import path 推测
+func extractImportRefs(html string, container ...string) ([]ImportRef, error) {
+	doc, err := getDoc(html)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []ImportRef
+	seen := make(map[string]bool)
+	findFirst(doc.Selection, container...).
+		Find("li a").
+		Each(func(i int, s *goquery.Selection) {
+			importPath := strings.TrimSpace(s.Text())
+			if importPath == "" || seen[importPath] {
+				return
+			}
+			seen[importPath] = true
+
+			module := s.Closest("details").ChildrenFiltered("summary").First().Text()
+			// summary 可能带着数量，例如 github.com/a/b (3)
+			module, _, _ = strings.Cut(strings.TrimSpace(module), " ")
+			refs = append(refs, newImportRef(importPath, module))
+		})
+	return refs, nil
+}
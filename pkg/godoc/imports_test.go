@@ -0,0 +1,84 @@
+package godoc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewImportList(t *testing.T) {
+	refs := []ImportRef{
+		{Path: "fmt", Module: "std"},
+		{Path: "github.com/pkg/errors", Module: "github.com/pkg/errors"},
+		{Path: "golang.org/x/mod/semver", Module: "golang.org/x/mod"},
+		{Path: "net/http", Module: "std"},
+		{Path: "golang.org/x/mod/module", Module: "golang.org/x/mod"},
+	}
+	tests := []struct {
+		name string
+		req  ImportsRequest
+		want *ImportList
+	}{
+		{
+			name: "default limit",
+			want: &ImportList{
+				Total: 5,
+				Std:   []string{"fmt", "net/http"},
+				Modules: []*ModuleImports{
+					{Module: "github.com/pkg/errors", Packages: []string{"github.com/pkg/errors"}},
+					{Module: "golang.org/x/mod", Packages: []string{"golang.org/x/mod/semver", "golang.org/x/mod/module"}},
+				},
+			},
+		},
+		{
+			name: "first page",
+			req:  ImportsRequest{Limit: 2},
+			want: &ImportList{
+				Total:    5,
+				Std:      []string{"fmt"},
+				Modules:  []*ModuleImports{{Module: "github.com/pkg/errors", Packages: []string{"github.com/pkg/errors"}}},
+				NextPage: 2,
+			},
+		},
+		{
+			name: "middle page",
+			req:  ImportsRequest{Page: 2, Limit: 2},
+			want: &ImportList{
+				Total:    5,
+				Std:      []string{"net/http"},
+				Modules:  []*ModuleImports{{Module: "golang.org/x/mod", Packages: []string{"golang.org/x/mod/semver"}}},
+				NextPage: 3,
+			},
+		},
+		{
+			name: "last page",
+			req:  ImportsRequest{Page: 3, Limit: 2},
+			want: &ImportList{
+				Total:   5,
+				Modules: []*ModuleImports{{Module: "golang.org/x/mod", Packages: []string{"golang.org/x/mod/module"}}},
+			},
+		},
+		{
+			name: "exactly full last page",
+			req:  ImportsRequest{Page: 1, Limit: 5},
+			want: &ImportList{
+				Total: 5,
+				Std:   []string{"fmt", "net/http"},
+				Modules: []*ModuleImports{
+					{Module: "github.com/pkg/errors", Packages: []string{"github.com/pkg/errors"}},
+					{Module: "golang.org/x/mod", Packages: []string{"golang.org/x/mod/semver", "golang.org/x/mod/module"}},
+				},
+			},
+		},
+		{
+			name: "page past the end",
+			req:  ImportsRequest{Page: 10, Limit: 2},
+			want: &ImportList{Total: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newImportList(refs, tt.req))
+		})
+	}
+}
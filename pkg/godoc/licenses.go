@@ -0,0 +1,99 @@
+package godoc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// LicenseList module 中检测到的 license
+type LicenseList struct {
+	Licenses []*LicenseInfo
+	// NonRedistributable pkg.go.dev 认为这个 module 不可再分发，此时不会显示文档
+	NonRedistributable bool
+}
+
+type LicenseInfo struct {
+	// Types license 的 SPDX 标识，例如 MIT，一个文件可能有多个
+	Types []string
+	// FilePath license 文件的路径，例如 github.com/pkg/errors@v0.9.1/LICENSE
+	FilePath string
+}
+
+// unknownLicense 标题中没有 license 类型时使用
+const unknownLicense = "UNKNOWN"
+
+// GetLicenses 获取包所在 module 的 license
+func GetLicenses(ctx context.Context, importPath string) (*LicenseList, error) {
+	p, err := DefaultProvider()
+	if err != nil {
+		return nil, err
+	}
+	return p.GetLicenses(ctx, importPath)
+}
+
+// splitLicenseTypes 拆分 "Apache-2.0, MIT"，None detected 返回 nil
+func splitLicenseTypes(s string) []string {
+	var types []string
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" || strings.EqualFold(t, "None detected") {
+			continue
+		}
+		types = append(types, t)
+	}
+	return types
+}
+
+func extractLicenseList(html string) (*LicenseList, error) {
+	doc, err := getDoc(html)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LicenseList{}
+	findFirst(doc.Selection, "section.License", ".License").
+		Each(func(i int, s *goquery.Selection) {
+			info := &LicenseInfo{
+				Types: splitLicenseTypes(findFirst(s, "h2", "h3").First().Text()),
+			}
+			source := strings.TrimSpace(s.Find(".License-source").First().Text())
+			info.FilePath = strings.TrimSpace(strings.TrimPrefix(source, "Source:"))
+			if len(info.Types) == 0 {
+				info.Types = []string{unknownLicense}
+			}
+			result.Licenses = append(result.Licenses, info)
+		})
+
+	result.NonRedistributable = isNonRedistributable(doc)
+	return result, nil
+}
+
+// isNonRedistributable 只看 pkg.go.dev 明确的标记：头部的 license 是 None detected，
+// 或者 Details 中的 Redistributable license 没有勾选。页面结构不认识时返回 false
+func isNonRedistributable(doc *goquery.Document) bool {
+	header := findFirst(doc.Selection,
+		"[data-test-id='UnitHeader-licenses']",
+		".UnitHeader-licenses",
+	).First().Text()
+	if strings.Contains(strings.ToLower(header), "none detected") {
+		return true
+	}
+	unchecked := false
+	doc.Find(".UnitMeta-details li").Each(func(i int, s *goquery.Selection) {
+		if strings.Contains(strings.Join(strings.Fields(s.Text()), " "), "Redistributable license") &&
+			s.Find("img[alt='unchecked']").Length() > 0 {
+			unchecked = true
+		}
+	})
+	return unchecked
+}
+
+// extractDocLicenses 文档页面头部的 license
+func extractDocLicenses(doc *goquery.Document) []string {
+	return splitLicenseTypes(findFirst(doc.Selection,
+		"[data-test-id='UnitHeader-licenses'] a",
+		".UnitHeader a[href*='tab=licenses']",
+	).First().Text())
+}
@@ -0,0 +1,713 @@
+package godoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// localEnv 本地查找包用到的目录
+type localEnv struct {
+	GOROOT     string
+	GOPATH     string
+	GOMODCACHE string
+}
+
+// LocalProvider 从 GOROOT, GOMODCACHE, GOPATH 中找到包的源码，用 go/doc 生成文档，不需要网络
+type LocalProvider struct {
+	env func() localEnv
+}
+
+// NewLocalProvider cfg 中为空的目录使用 go env 的值
+func NewLocalProvider(cfg LocalConfig) *LocalProvider {
+	return &LocalProvider{
+		env: sync.OnceValue(func() localEnv {
+			return resolveLocalEnv(cfg)
+		}),
+	}
+}
+
+func (p *LocalProvider) Name() string {
+	return "local"
+}
+
+// Search 本地没有索引，不支持搜索
+func (p *LocalProvider) Search(ctx context.Context, req SearchRequest) (*SearchResult, error) {
+	return nil, ErrNotSupported
+}
+
+func (p *LocalProvider) GetPackageDocument(ctx context.Context, req GetPackageRequest) (*PackageDocument, error) {
+	importPath, version := req.pathAndVersion()
+	env := p.env()
+	pkg, err := resolveLocalDir(env, importPath, version)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).DebugContext(ctx, "read package from local", "package", importPath, "version", pkg.Version, "dir", pkg.Dir)
+	reportProgress(ctx, "parsing %s", pkg.Dir)
+
+	return extractLocalDoc(env, pkg, importPath, req)
+}
+
+// GetLicenses 本地不识别 license 的类型，不支持
+func (p *LocalProvider) GetLicenses(ctx context.Context, importPath string) (*LicenseList, error) {
+	return nil, ErrNotSupported
+}
+
+// GetImports 只包括非测试文件的导入
+func (p *LocalProvider) GetImports(ctx context.Context, importPath string) ([]ImportRef, error) {
+	importPath, version := splitVersion(importPath)
+	env := p.env()
+	pkg, err := resolveLocalDir(env, importPath, version)
+	if err != nil {
+		return nil, err
+	}
+
+	bctx := build.Default
+	bctx.GOROOT = env.GOROOT
+	bctx.GOPATH = env.GOPATH
+	bp, err := bctx.ImportDir(pkg.Dir, 0)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	refs := make([]ImportRef, 0, len(bp.Imports))
+	for _, imp := range bp.Imports {
+		refs = append(refs, newImportRef(imp, ""))
+	}
+	return refs, nil
+}
+
+// GetImportedBy 本地没有索引，不支持
+func (p *LocalProvider) GetImportedBy(ctx context.Context, importPath string) ([]ImportRef, error) {
+	return nil, ErrNotSupported
+}
+
+// ListVersions 列出 GOMODCACHE 中已经下载的版本，标准库和 GOPATH 中的包没有版本
+func (p *LocalProvider) ListVersions(ctx context.Context, importPath string) (*VersionList, error) {
+	importPath, _ = splitVersion(importPath)
+	env := p.env()
+	if env.GOMODCACHE == "" {
+		return nil, ErrNotSupported
+	}
+
+	elems := strings.Split(strings.Trim(importPath, "/"), "/")
+	for i := len(elems); i > 0; i-- {
+		escaped, err := module.EscapePath(strings.Join(elems[:i], "/"))
+		if err != nil {
+			continue
+		}
+		parent := filepath.Join(env.GOMODCACHE, filepath.FromSlash(path.Dir(escaped)))
+		extracted := localModuleVersions(parent, path.Base(escaped))
+		versions, _ := localDownloadedVersions(filepath.Join(env.GOMODCACHE, "cache", "download", filepath.FromSlash(escaped), "@v"))
+		if len(extracted) == 0 && len(versions) == 0 {
+			continue
+		}
+		for v := range extracted {
+			if _, ok := versions[v]; !ok {
+				versions[v] = &VersionInfo{Version: v}
+			}
+		}
+
+		// 和 pkg.go.dev 一样，有正式的 tag 时不列出伪版本
+		list := slices.Collect(maps.Values(versions))
+		if slices.ContainsFunc(list, func(v *VersionInfo) bool { return !module.IsPseudoVersion(v.Version) }) {
+			list = slices.DeleteFunc(list, func(v *VersionInfo) bool { return module.IsPseudoVersion(v.Version) })
+		}
+		return newVersionList(list), nil
+	}
+	return nil, errors.Errorf("no version of %s found in GOMODCACHE %s", importPath, env.GOMODCACHE)
+}
+
+// resolveLocalEnv 优先使用配置，其次使用 go env，最后使用 go/build 的默认值
+func resolveLocalEnv(cfg LocalConfig) localEnv {
+	env := localEnv{
+		GOROOT:     cfg.GOROOT,
+		GOPATH:     cfg.GOPATH,
+		GOMODCACHE: cfg.GOMODCACHE,
+	}
+	if env.GOROOT != "" && env.GOPATH != "" && env.GOMODCACHE != "" {
+		return env
+	}
+
+	goEnv := readGoEnv()
+	if env.GOROOT == "" {
+		env.GOROOT = goEnv.GOROOT
+	}
+	if env.GOPATH == "" {
+		env.GOPATH = goEnv.GOPATH
+	}
+	if env.GOMODCACHE == "" {
+		env.GOMODCACHE = goEnv.GOMODCACHE
+	}
+	if env.GOMODCACHE == "" {
+		if gopaths := filepath.SplitList(env.GOPATH); len(gopaths) > 0 {
+			env.GOMODCACHE = filepath.Join(gopaths[0], "pkg", "mod")
+		}
+	}
+	return env
+}
+
+func readGoEnv() localEnv {
+	env := localEnv{
+		GOROOT:     build.Default.GOROOT,
+		GOPATH:     build.Default.GOPATH,
+		GOMODCACHE: os.Getenv("GOMODCACHE"),
+	}
+	fromGo, err := goEnvValues("GOROOT", "GOPATH", "GOMODCACHE")
+	if err != nil {
+		return env
+	}
+	if fromGo["GOROOT"] != "" {
+		env.GOROOT = fromGo["GOROOT"]
+	}
+	if fromGo["GOPATH"] != "" {
+		env.GOPATH = fromGo["GOPATH"]
+	}
+	if fromGo["GOMODCACHE"] != "" {
+		env.GOMODCACHE = fromGo["GOMODCACHE"]
+	}
+	return env
+}
+
+// goEnvValues 通过 go env 读取，包括 go env -w 写入的值
+func goEnvValues(keys ...string) (map[string]string, error) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	out, err := exec.Command(goBin, append([]string{"env", "-json"}, keys...)...).Output()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	values := make(map[string]string, len(keys))
+	if err := json.Unmarshal(out, &values); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return values, nil
+}
+
+// splitVersion 把 path@version 拆开，没有版本时 version 为空
+func splitVersion(pkgName string) (string, string) {
+	if i := strings.LastIndex(pkgName, "@"); i >= 0 && !strings.Contains(pkgName[i:], "/") {
+		return pkgName[:i], pkgName[i+1:]
+	}
+	return pkgName, ""
+}
+
+// localPackage resolveLocalDir 找到的包
+type localPackage struct {
+	Dir string
+	// ModulePath 和 ModuleDir 是包所在的 module，标准库是 std 和 GOROOT/src，GOPATH 中的包为空
+	ModulePath string
+	ModuleDir  string
+	// Version 标准库的版本是 GOROOT 的 go 版本，GOPATH 中的包没有版本
+	Version string
+}
+
+// resolveLocalDir 依次在 GOROOT, GOMODCACHE, GOPATH 中查找 import path 对应的目录
+func resolveLocalDir(env localEnv, importPath, version string) (localPackage, error) {
+	importPath = strings.Trim(importPath, "/")
+	if importPath == "" {
+		return localPackage{}, errors.New("empty import path")
+	}
+
+	// 标准库的路径第一段没有 .
+	first, _, _ := strings.Cut(importPath, "/")
+	if !strings.Contains(first, ".") {
+		if env.GOROOT != "" {
+			dir := filepath.Join(env.GOROOT, "src", filepath.FromSlash(importPath))
+			if isDir(dir) {
+				return localPackage{
+					Dir:        dir,
+					ModulePath: "std",
+					ModuleDir:  filepath.Join(env.GOROOT, "src"),
+					Version:    goRootVersion(env.GOROOT),
+				}, nil
+			}
+		}
+		return localPackage{}, errors.Errorf("cannot find package %s in GOROOT %s", importPath, env.GOROOT)
+	}
+
+	if env.GOMODCACHE != "" {
+		if pkg, ok := resolveModCacheDir(env.GOMODCACHE, importPath, version); ok {
+			return pkg, nil
+		}
+	}
+
+	// GOPATH 中没有版本，指定了版本时不使用
+	if version == "" {
+		for _, gopath := range filepath.SplitList(env.GOPATH) {
+			dir := filepath.Join(gopath, "src", filepath.FromSlash(importPath))
+			if isDir(dir) {
+				return localPackage{Dir: dir}, nil
+			}
+		}
+	}
+
+	if version != "" {
+		return localPackage{}, errors.Errorf("cannot find package %s@%s in GOMODCACHE %s", importPath, version, env.GOMODCACHE)
+	}
+	return localPackage{}, errors.Errorf("cannot find package %s in GOMODCACHE %s or GOPATH %s", importPath, env.GOMODCACHE, env.GOPATH)
+}
+
+// goRootVersion 读取 GOROOT/VERSION 的第一行，例如 go1.25.0
+func goRootVersion(goroot string) string {
+	b, err := os.ReadFile(filepath.Join(goroot, "VERSION"))
+	if err != nil {
+		return ""
+	}
+	version, _, _ := strings.Cut(string(b), "\n")
+	return strings.TrimSpace(version)
+}
+
+// resolveModCacheDir 从最长的前缀开始把 import path 当作 module path 在 GOMODCACHE 中查找
+// 没有指定版本时使用本地最新的正式版本，没有正式版本时使用最新的预发布版本
+func resolveModCacheDir(modCache, importPath, version string) (localPackage, bool) {
+	elems := strings.Split(importPath, "/")
+	for i := len(elems); i > 0; i-- {
+		modPath := strings.Join(elems[:i], "/")
+		rest := strings.Join(elems[i:], "/")
+
+		escaped, err := module.EscapePath(modPath)
+		if err != nil {
+			continue
+		}
+		parent := filepath.Join(modCache, filepath.FromSlash(path.Dir(escaped)))
+		versions := localModuleVersions(parent, path.Base(escaped))
+		if len(versions) == 0 {
+			continue
+		}
+
+		v := version
+		if v == "" {
+			v = latestVersion(versions)
+		}
+		escapedVersion, ok := versions[v]
+		if !ok {
+			continue
+		}
+		moduleDir := filepath.Join(parent, path.Base(escaped)+"@"+escapedVersion)
+		dir := filepath.Join(moduleDir, filepath.FromSlash(rest))
+		if isDir(dir) {
+			return localPackage{
+				Dir:        dir,
+				ModulePath: modPath,
+				ModuleDir:  moduleDir,
+				Version:    v,
+			}, true
+		}
+	}
+	return localPackage{}, false
+}
+
+// localModuleVersions 返回 GOMODCACHE 中 module 的所有版本，key 是版本，value 是转义后的版本
+func localModuleVersions(parent, escapedBase string) map[string]string {
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return nil
+	}
+	versions := make(map[string]string)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		escapedVersion, ok := strings.CutPrefix(e.Name(), escapedBase+"@")
+		if !ok {
+			continue
+		}
+		v, err := module.UnescapeVersion(escapedVersion)
+		if err != nil || !semver.IsValid(v) {
+			continue
+		}
+		versions[v] = escapedVersion
+	}
+	return versions
+}
+
+// localDownloadedVersions 读取 GOMODCACHE/cache/download 中的 .info 文件，包括只下载了 go.mod 的版本
+// 撤回的版本根据其中最新的 go.mod 的 retract 判断，同时返回这个 go.mod，没有时为 nil
+func localDownloadedVersions(dir string) (map[string]*VersionInfo, *modfile.File) {
+	versions := make(map[string]*VersionInfo)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return versions, nil
+	}
+	for _, e := range entries {
+		escapedVersion, ok := strings.CutSuffix(e.Name(), ".info")
+		if !ok {
+			continue
+		}
+		v, err := module.UnescapeVersion(escapedVersion)
+		if err != nil || !semver.IsValid(v) {
+			continue
+		}
+		versions[v] = &VersionInfo{
+			Version:   v,
+			Published: readInfoTime(filepath.Join(dir, e.Name())),
+		}
+	}
+
+	var latest string
+	for v := range versions {
+		if latest == "" || semver.Compare(v, latest) > 0 {
+			latest = v
+		}
+	}
+	if latest == "" {
+		return versions, nil
+	}
+	escapedLatest, err := module.EscapeVersion(latest)
+	if err != nil {
+		return versions, nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, escapedLatest+".mod"))
+	if err != nil {
+		return versions, nil
+	}
+	f, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return versions, nil
+	}
+	for _, r := range f.Retract {
+		for v, info := range versions {
+			if semver.Compare(v, r.Low) >= 0 && semver.Compare(v, r.High) <= 0 {
+				info.Retracted = true
+				info.RetractRationale = r.Rationale
+			}
+		}
+	}
+	return versions, f
+}
+
+// readInfoTime 读取 GOMODCACHE/cache/download 中 .info 文件的发布时间，失败时返回零值
+func readInfoTime(file string) time.Time {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return time.Time{}
+	}
+	var info struct{ Time time.Time }
+	if json.Unmarshal(b, &info) != nil {
+		return time.Time{}
+	}
+	return info.Time
+}
+
+func latestVersion(versions map[string]string) string {
+	var latest, latestPre string
+	for v := range versions {
+		if semver.Prerelease(v) != "" {
+			if latestPre == "" || semver.Compare(v, latestPre) > 0 {
+				latestPre = v
+			}
+			continue
+		}
+		if latest == "" || semver.Compare(v, latest) > 0 {
+			latest = v
+		}
+	}
+	if latest != "" {
+		return latest
+	}
+	return latestPre
+}
+
+func isDir(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// extractLocalDoc 解析目录下的源码，生成和 pkg.go.dev 一样结构的文档
+func extractLocalDoc(env localEnv, lp localPackage, importPath string, req GetPackageRequest) (*PackageDocument, error) {
+	dir := lp.Dir
+	subPackages, err := extractLocalSubPackages(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	bctx := build.Default
+	bctx.GOROOT = env.GOROOT
+	bctx.GOPATH = env.GOPATH
+	bp, err := bctx.ImportDir(dir, 0)
+	if err != nil {
+		var noGo *build.NoGoError
+		if errors.As(err, &noGo) {
+			// 只有子目录的目录，和 pkg.go.dev 一样只返回子包
+			return &PackageDocument{
+				Version:     lp.Version,
+				Module:      localModuleInfo(env, lp, ""),
+				SubPackages: subPackages,
+			}, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, names := range [][]string{bp.GoFiles, bp.CgoFiles, bp.TestGoFiles, bp.XTestGoFiles} {
+		for _, name := range names {
+			f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			files = append(files, f)
+		}
+	}
+
+	pkg, err := doc.NewFromFiles(fset, files, importPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	d := localDoc{fset: fset, needURL: req.NeedURL}
+	result := &PackageDocument{
+		Version:     lp.Version,
+		Module:      localModuleInfo(env, lp, bp.Name),
+		Overview:    strings.TrimSpace(pkg.Doc),
+		SubPackages: subPackages,
+	}
+
+	for _, c := range pkg.Consts {
+		result.Consts = append(result.Consts, ConstBlock(d.value(c)))
+	}
+	for _, v := range pkg.Vars {
+		result.Variables = append(result.Variables, VariableBlock(d.value(v)))
+	}
+	for _, f := range pkg.Funcs {
+		result.Functions = append(result.Functions, FunctionBlock(d.function(f)))
+	}
+	result.Examples = append(result.Examples, d.examples("", pkg.Examples)...)
+	for _, f := range pkg.Funcs {
+		result.Examples = append(result.Examples, d.examples(f.Name, f.Examples)...)
+	}
+
+	for _, t := range pkg.Types {
+		tb := TypeBlock{
+			SourceURL:  d.sourceURL(t.Decl),
+			Definition: d.print(t.Decl),
+			Comment:    strings.TrimSpace(t.Doc),
+		}
+		// pkg.go.dev 把有类型的常量和变量放在类型下面，这里没有对应的字段，放到包级别
+		for _, c := range t.Consts {
+			result.Consts = append(result.Consts, ConstBlock(d.value(c)))
+		}
+		for _, v := range t.Vars {
+			result.Variables = append(result.Variables, VariableBlock(d.value(v)))
+		}
+		for _, f := range t.Funcs {
+			tb.TypeFunctions = append(tb.TypeFunctions, TypeFunction(d.function(f)))
+		}
+		for _, m := range t.Methods {
+			tb.TypeMethods = append(tb.TypeMethods, TypeMethod(d.function(m)))
+		}
+		result.Types = append(result.Types, tb)
+
+		result.Examples = append(result.Examples, d.examples(t.Name, t.Examples)...)
+		for _, f := range t.Funcs {
+			result.Examples = append(result.Examples, d.examples(f.Name, f.Examples)...)
+		}
+		for _, m := range t.Methods {
+			result.Examples = append(result.Examples, d.examples(t.Name+"."+m.Name, m.Examples)...)
+		}
+	}
+
+	result.Deprecated = parseDeprecation(pkg.Doc)
+	markDeprecations(result)
+	return result, nil
+}
+
+// localBlock 和 ConstBlock 等结构体的字段一致，方便转换
+type localBlock struct {
+	SourceURL  string
+	Definition string
+	Comment    string
+	Deprecated *Deprecation `json:",omitempty"`
+}
+
+type localDoc struct {
+	fset    *token.FileSet
+	needURL bool
+}
+
+func (d localDoc) value(v *doc.Value) localBlock {
+	return localBlock{
+		SourceURL:  d.sourceURL(v.Decl),
+		Definition: d.print(v.Decl),
+		Comment:    strings.TrimSpace(v.Doc),
+	}
+}
+
+func (d localDoc) function(f *doc.Func) localBlock {
+	return localBlock{
+		SourceURL:  d.sourceURL(f.Decl),
+		Definition: d.print(f.Decl),
+		Comment:    strings.TrimSpace(f.Doc),
+	}
+}
+
+// examples 按 pkg.go.dev 的方式命名，例如 Example, Example (Suffix), Type.Method (Suffix)
+func (d localDoc) examples(name string, examples []*doc.Example) []ExampleBlock {
+	var blocks []ExampleBlock
+	for _, ex := range examples {
+		exName := name
+		if exName == "" {
+			exName = "Example"
+		}
+		if ex.Suffix != "" {
+			exName += " (" + ex.Suffix + ")"
+		}
+		var code string
+		if ex.Play != nil {
+			code = d.print(ex.Play)
+		} else {
+			code = d.print(ex.Code)
+			code = strings.TrimSuffix(strings.TrimPrefix(code, "{"), "}")
+		}
+		blocks = append(blocks, ExampleBlock{
+			Name:   exName,
+			Code:   strings.TrimSpace(code),
+			Output: strings.TrimSpace(ex.Output),
+		})
+	}
+	return blocks
+}
+
+func (d localDoc) print(node any) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, d.fset, node); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func (d localDoc) sourceURL(node ast.Node) string {
+	if !d.needURL || node == nil {
+		return ""
+	}
+	pos := d.fset.Position(node.Pos())
+	return fmt.Sprintf("file://%s#L%d", filepath.ToSlash(pos.Filename), pos.Line)
+}
+
+// localModuleInfo 本地不检测 license，RedistributableLicense 总是 false
+func localModuleInfo(env localEnv, lp localPackage, packageName string) *ModuleInfo {
+	info := &ModuleInfo{
+		Path:        lp.ModulePath,
+		Version:     lp.Version,
+		PackageName: packageName,
+	}
+	// 和 go 命令一样，module 的弃用以最新版本的 go.mod 为准，本地没有下载过其他版本时使用当前版本的
+	var goMod *modfile.File
+	if lp.ModuleDir != "" {
+		data, err := os.ReadFile(filepath.Join(lp.ModuleDir, "go.mod"))
+		info.ValidGoMod = err == nil
+		goMod, _ = modfile.ParseLax("go.mod", data, nil)
+	}
+	switch {
+	case lp.ModulePath == "std":
+		// 和 pkg.go.dev 一样，标准库的版本都是正式的版本
+		info.TaggedVersion = lp.Version != ""
+		info.StableVersion = lp.Version != ""
+	case lp.Version != "":
+		info.TaggedVersion = !module.IsPseudoVersion(lp.Version)
+		info.StableVersion = info.TaggedVersion && semver.Major(lp.Version) != "v0" && semver.Prerelease(lp.Version) == ""
+		if escaped, err := module.EscapePath(lp.ModulePath); err == nil {
+			dir := filepath.Join(env.GOMODCACHE, "cache", "download", filepath.FromSlash(escaped), "@v")
+			versions, latestMod := localDownloadedVersions(dir)
+			if v, ok := versions[lp.Version]; ok {
+				info.Published = v.Published
+				info.Retracted = v.Retracted
+				info.RetractRationale = v.RetractRationale
+			}
+			if latestMod != nil {
+				goMod = latestMod
+			}
+		}
+	}
+	if goMod != nil && goMod.Module != nil && goMod.Module.Deprecated != "" {
+		info.Deprecated = newDeprecation(goMod.Module.Deprecated)
+	}
+	return info
+}
+
+// extractLocalSubPackages 遍历子目录，不进入 testdata、以 . 或 _ 开头的目录以及嵌套的 module
+func extractLocalSubPackages(root string) ([]*SubPackage, error) {
+	var subPackages []*SubPackage
+	err := filepath.WalkDir(root, func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !e.IsDir() || p == root {
+			return nil
+		}
+		name := e.Name()
+		if name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+			return filepath.SkipDir
+		}
+
+		synopsis, ok := localSynopsis(p)
+		if !ok {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		subPackages = append(subPackages, &SubPackage{
+			Name:    filepath.ToSlash(rel),
+			Comment: synopsis,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return subPackages, nil
+}
+
+// localSynopsis 只解析 package 语句和注释，返回包注释的第一句话，目录下没有 go 文件时 ok 为 false
+func localSynopsis(dir string) (synopsis string, ok bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		ok = true
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil || f.Doc == nil {
+			continue
+		}
+		return new(doc.Package).Synopsis(f.Doc.Text()), true
+	}
+	return "", ok
+}
@@ -0,0 +1,349 @@
+package godoc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles 在 root 下创建文件，key 是 / 分隔的相对路径，以 / 结尾时创建空目录
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if name[len(name)-1] == '/' {
+			require.NoError(t, os.MkdirAll(p, 0o755))
+			continue
+		}
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+}
+
+func TestSplitVersion(t *testing.T) {
+	tests := []struct {
+		pkgName string
+		path    string
+		version string
+	}{
+		{"github.com/BurntSushi/toml", "github.com/BurntSushi/toml", ""},
+		{"github.com/BurntSushi/toml@v1.3.2", "github.com/BurntSushi/toml", "v1.3.2"},
+		{"net/http@go1.21.3", "net/http", "go1.21.3"},
+		{"example.com/mod@v1.2.3/sub", "example.com/mod@v1.2.3/sub", ""},
+		{"example.com/mod@", "example.com/mod", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pkgName, func(t *testing.T) {
+			path, version := splitVersion(tt.pkgName)
+			assert.Equal(t, tt.path, path)
+			assert.Equal(t, tt.version, version)
+		})
+	}
+}
+
+func TestLocalModuleVersions(t *testing.T) {
+	parent := t.TempDir()
+	writeFiles(t, parent, map[string]string{
+		"toml@v1.2.0/":        "",
+		"toml@v1.3.0-!r!c1/":  "",
+		"toml@latest/":        "",
+		"toml@v1.4.0":         "not a directory",
+		"tomlx@v1.0.0/":       "",
+		"toml/v2@v2.0.0/":     "",
+		"toml@v1.0.0-!bad!/":  "",
+		"toml@v1.1.0+incomp/": "",
+	})
+	assert.Equal(t, map[string]string{
+		"v1.2.0":        "v1.2.0",
+		"v1.3.0-RC1":    "v1.3.0-!r!c1",
+		"v1.1.0+incomp": "v1.1.0+incomp",
+	}, localModuleVersions(parent, "toml"))
+	assert.Empty(t, localModuleVersions(filepath.Join(parent, "missing"), "toml"))
+}
+
+func TestResolveModCacheDir(t *testing.T) {
+	modCache := t.TempDir()
+	writeFiles(t, modCache, map[string]string{
+		"github.com/!burnt!sushi/toml@v1.2.0/decode.go":      "package toml",
+		"github.com/!burnt!sushi/toml@v1.3.0/decode.go":      "package toml",
+		"github.com/!burnt!sushi/toml@v1.3.0/internal/tz.go": "package internal",
+		"github.com/!burnt!sushi/toml@v1.4.0-rc.1/decode.go": "package toml",
+		"example.com/pre@v0.1.0-alpha/pre.go":                "package pre",
+		"example.com/pre@v0.1.0-beta/pre.go":                 "package pre",
+		"example.com/mod@v1.0.0/sub/pkg/pkg.go":              "package pkg",
+		"example.com/mod/sub@v1.1.0/pkg/pkg.go":              "package pkg",
+		"example.com/mod/sub@v1.1.0/go.mod":                  "module example.com/mod/sub",
+		"cache/download/example.com/mod/@v/v1.0.0.info":      "{}",
+	})
+
+	tests := []struct {
+		name       string
+		importPath string
+		version    string
+		want       localPackage
+		ok         bool
+	}{
+		{
+			name:       "latest release over newer prerelease",
+			importPath: "github.com/BurntSushi/toml",
+			want: localPackage{
+				Dir:        filepath.Join(modCache, "github.com/!burnt!sushi/toml@v1.3.0"),
+				ModulePath: "github.com/BurntSushi/toml",
+				ModuleDir:  filepath.Join(modCache, "github.com/!burnt!sushi/toml@v1.3.0"),
+				Version:    "v1.3.0",
+			},
+			ok: true,
+		},
+		{
+			name:       "explicit prerelease",
+			importPath: "github.com/BurntSushi/toml",
+			version:    "v1.4.0-rc.1",
+			want: localPackage{
+				Dir:        filepath.Join(modCache, "github.com/!burnt!sushi/toml@v1.4.0-rc.1"),
+				ModulePath: "github.com/BurntSushi/toml",
+				ModuleDir:  filepath.Join(modCache, "github.com/!burnt!sushi/toml@v1.4.0-rc.1"),
+				Version:    "v1.4.0-rc.1",
+			},
+			ok: true,
+		},
+		{
+			name:       "package in module",
+			importPath: "github.com/BurntSushi/toml/internal",
+			version:    "v1.3.0",
+			want: localPackage{
+				Dir:        filepath.Join(modCache, "github.com/!burnt!sushi/toml@v1.3.0/internal"),
+				ModulePath: "github.com/BurntSushi/toml",
+				ModuleDir:  filepath.Join(modCache, "github.com/!burnt!sushi/toml@v1.3.0"),
+				Version:    "v1.3.0",
+			},
+			ok: true,
+		},
+		{
+			name:       "only prereleases",
+			importPath: "example.com/pre",
+			want: localPackage{
+				Dir:        filepath.Join(modCache, "example.com/pre@v0.1.0-beta"),
+				ModulePath: "example.com/pre",
+				ModuleDir:  filepath.Join(modCache, "example.com/pre@v0.1.0-beta"),
+				Version:    "v0.1.0-beta",
+			},
+			ok: true,
+		},
+		{
+			name:       "nested module wins",
+			importPath: "example.com/mod/sub/pkg",
+			want: localPackage{
+				Dir:        filepath.Join(modCache, "example.com/mod/sub@v1.1.0/pkg"),
+				ModulePath: "example.com/mod/sub",
+				ModuleDir:  filepath.Join(modCache, "example.com/mod/sub@v1.1.0"),
+				Version:    "v1.1.0",
+			},
+			ok: true,
+		},
+		{
+			name:       "outer module at its version",
+			importPath: "example.com/mod/sub/pkg",
+			version:    "v1.0.0",
+			want: localPackage{
+				Dir:        filepath.Join(modCache, "example.com/mod@v1.0.0/sub/pkg"),
+				ModulePath: "example.com/mod",
+				ModuleDir:  filepath.Join(modCache, "example.com/mod@v1.0.0"),
+				Version:    "v1.0.0",
+			},
+			ok: true,
+		},
+		{
+			name:       "version not downloaded",
+			importPath: "github.com/BurntSushi/toml",
+			version:    "v0.9.0",
+		},
+		{
+			name:       "package not in module",
+			importPath: "github.com/BurntSushi/toml/missing",
+		},
+		{
+			name:       "unknown module",
+			importPath: "example.com/unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := resolveModCacheDir(modCache, tt.importPath, tt.version)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveLocalDir(t *testing.T) {
+	root := t.TempDir()
+	env := localEnv{
+		GOROOT:     filepath.Join(root, "goroot"),
+		GOPATH:     filepath.Join(root, "gopath"),
+		GOMODCACHE: filepath.Join(root, "gopath/pkg/mod"),
+	}
+	writeFiles(t, root, map[string]string{
+		"goroot/VERSION":                          "go1.25.0\ntime 2025-08-12T00:00:00Z\n",
+		"goroot/src/net/http/server.go":           "package http",
+		"gopath/src/example.com/old/old.go":       "package old",
+		"gopath/pkg/mod/example.com/mod@v1.0.0/":  "",
+		"gopath/src/example.com/both/both.go":     "package both",
+		"gopath/pkg/mod/example.com/both@v1.0.0/": "",
+	})
+
+	tests := []struct {
+		name       string
+		importPath string
+		version    string
+		want       localPackage
+		wantErr    string
+	}{
+		{
+			name:       "std",
+			importPath: "net/http",
+			want: localPackage{
+				Dir:        filepath.Join(env.GOROOT, "src/net/http"),
+				ModulePath: "std",
+				ModuleDir:  filepath.Join(env.GOROOT, "src"),
+				Version:    "go1.25.0",
+			},
+		},
+		{
+			name:       "module cache before GOPATH",
+			importPath: "/example.com/both/",
+			want: localPackage{
+				Dir:        filepath.Join(env.GOMODCACHE, "example.com/both@v1.0.0"),
+				ModulePath: "example.com/both",
+				ModuleDir:  filepath.Join(env.GOMODCACHE, "example.com/both@v1.0.0"),
+				Version:    "v1.0.0",
+			},
+		},
+		{
+			name:       "GOPATH",
+			importPath: "example.com/old",
+			want:       localPackage{Dir: filepath.Join(env.GOPATH, "src/example.com/old")},
+		},
+		{
+			name:       "GOPATH ignored with version",
+			importPath: "example.com/old",
+			version:    "v1.0.0",
+			wantErr:    "cannot find package example.com/old@v1.0.0",
+		},
+		{
+			name:       "std not found",
+			importPath: "net/missing",
+			wantErr:    "cannot find package net/missing in GOROOT",
+		},
+		{
+			name:       "empty",
+			importPath: "/",
+			wantErr:    "empty import path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveLocalDir(env, tt.importPath, tt.version)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLocalDownloadedVersions(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"v1.0.0.info":       `{"Version":"v1.0.0","Time":"2023-01-02T03:04:05Z"}`,
+		"v1.1.0.info":       `{"Version":"v1.1.0","Time":"2023-06-01T00:00:00Z"}`,
+		"v1.2.0-!r!c1.info": `{"Version":"v1.2.0-RC1"}`,
+		"v1.2.0.info":       `{"Version":"v1.2.0","Time":"2024-01-01T00:00:00Z"}`,
+		"v1.2.0.mod": `// Deprecated: use example.com/mod/v2 instead.
+module example.com/mod
+
+retract (
+	v1.0.0 // published by accident
+	[v1.1.0, v1.2.0-RC1] // broken build
+)
+`,
+		"v1.0.0.mod":     "module example.com/mod\n",
+		"list":           "v1.0.0\nv1.1.0\n",
+		"latest.info":    `{"Version":"v1.2.0"}`,
+		"v1.0.0.ziphash": "h1:",
+	})
+
+	versions, goMod := localDownloadedVersions(dir)
+	assert.Equal(t, map[string]*VersionInfo{
+		"v1.0.0": {
+			Version:          "v1.0.0",
+			Published:        time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+			Retracted:        true,
+			RetractRationale: "published by accident",
+		},
+		"v1.1.0": {
+			Version:          "v1.1.0",
+			Published:        time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+			Retracted:        true,
+			RetractRationale: "broken build",
+		},
+		"v1.2.0-RC1": {
+			Version:          "v1.2.0-RC1",
+			Retracted:        true,
+			RetractRationale: "broken build",
+		},
+		"v1.2.0": {
+			Version:   "v1.2.0",
+			Published: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}, versions)
+	require.NotNil(t, goMod)
+	assert.Equal(t, "use example.com/mod/v2 instead.", goMod.Module.Deprecated)
+
+	t.Run("latest without go.mod", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"v1.0.0.info": "{}"})
+		versions, goMod := localDownloadedVersions(dir)
+		assert.Len(t, versions, 1)
+		assert.Nil(t, goMod)
+	})
+	t.Run("missing dir", func(t *testing.T) {
+		versions, goMod := localDownloadedVersions(filepath.Join(dir, "missing"))
+		assert.Empty(t, versions)
+		assert.Nil(t, goMod)
+	})
+}
+
+func TestExtractLocalSubPackages(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"root.go":                  "package root",
+		"a/a.go":                   "// Package a does a. And more.\npackage a",
+		"a/a_test.go":              "// Package a_test is ignored.\npackage a_test",
+		"a/testdata/fixture.go":    "package fixture",
+		"b/c/c.go":                 "package c",
+		"b/README":                 "",
+		"nested/go.mod":            "module example.com/root/nested",
+		"nested/nested.go":         "package nested",
+		"_examples/main.go":        "package main",
+		".git/hooks/hook.go":       "package hooks",
+		"empty/":                   "",
+		"onlytest/only_test.go":    "package onlytest",
+		"cmd/tool/main.go":         "// Tool is a command.\npackage main",
+		"cmd/tool/internal/x/x.go": "package x",
+	})
+
+	subPackages, err := extractLocalSubPackages(root)
+	require.NoError(t, err)
+	assert.Equal(t, []*SubPackage{
+		{Name: "a", Comment: "Package a does a."},
+		{Name: "b/c"},
+		{Name: "cmd/tool", Comment: "Tool is a command."},
+		{Name: "cmd/tool/internal/x"},
+	}, subPackages)
+}
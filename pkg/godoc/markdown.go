@@ -0,0 +1,168 @@
+package godoc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Markdown 把文档渲染成 markdown，给 resource 之类需要纯文本的场景使用
+// title 一般是包的 import path
+func (d *PackageDocument) Markdown(title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if d.Version != "" {
+		fmt.Fprintf(&b, "Version: %s\n\n", d.Version)
+	}
+	if len(d.Licenses) > 0 {
+		fmt.Fprintf(&b, "License: %s\n\n", strings.Join(d.Licenses, ", "))
+	}
+	if d.Module != nil {
+		if d.Module.PackageName != "" {
+			fmt.Fprintf(&b, "Package name: %s\n\n", d.Module.PackageName)
+		}
+		if d.Module.Path != "" {
+			fmt.Fprintf(&b, "Module: %s\n\n", d.Module.Path)
+		}
+		if d.Module.RepositoryURL != "" {
+			fmt.Fprintf(&b, "Repository: %s\n\n", d.Module.RepositoryURL)
+		}
+		if d.Module.Deprecated != nil {
+			fmt.Fprintf(&b, "**The module is deprecated**: %s\n\n", d.Module.Deprecated.Message)
+		}
+		if d.Module.Retracted {
+			fmt.Fprintf(&b, "**This version is retracted**. %s\n\n", d.Module.RetractRationale)
+		}
+	}
+	if len(d.Vulns) > 0 {
+		b.WriteString("**Known vulnerabilities in this version**:\n\n")
+		for _, v := range d.Vulns {
+			fmt.Fprintf(&b, "- %s %s", v.ID, v.Summary)
+			if v.Fixed != "" {
+				fmt.Fprintf(&b, " (fixed in %s)", v.Fixed)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	if d.Overview != "" {
+		writeParagraph(&b, d.Overview)
+	}
+
+	if len(d.Consts) > 0 {
+		b.WriteString("## Constants\n\n")
+		for _, c := range d.Consts {
+			writeDecl(&b, c.Definition, c.Comment, c.SourceURL)
+		}
+	}
+	if len(d.Variables) > 0 {
+		b.WriteString("## Variables\n\n")
+		for _, v := range d.Variables {
+			writeDecl(&b, v.Definition, v.Comment, v.SourceURL)
+		}
+	}
+	if len(d.Functions) > 0 {
+		b.WriteString("## Functions\n\n")
+		for _, f := range d.Functions {
+			writeDecl(&b, f.Definition, f.Comment, f.SourceURL)
+		}
+	}
+	if len(d.Types) > 0 {
+		b.WriteString("## Types\n\n")
+		for _, t := range d.Types {
+			writeDecl(&b, t.Definition, t.Comment, t.SourceURL)
+			for _, f := range t.TypeFunctions {
+				writeDecl(&b, f.Definition, f.Comment, f.SourceURL)
+			}
+			for _, m := range t.TypeMethods {
+				writeDecl(&b, m.Definition, m.Comment, m.SourceURL)
+			}
+		}
+	}
+	if len(d.Examples) > 0 {
+		b.WriteString("## Examples\n\n")
+		for _, e := range d.Examples {
+			fmt.Fprintf(&b, "### %s\n\n", e.Name)
+			writeCode(&b, e.Code)
+			if e.Output != "" {
+				b.WriteString("Output:\n\n")
+				writeCode(&b, e.Output)
+			}
+		}
+	}
+	if len(d.SubPackages) > 0 {
+		b.WriteString("## Subpackages\n\n")
+		for _, p := range d.SubPackages {
+			if p.Comment == "" {
+				fmt.Fprintf(&b, "- %s\n", p.Name)
+				continue
+			}
+			fmt.Fprintf(&b, "- %s: %s\n", p.Name, p.Comment)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// Markdown 把搜索结果渲染成 markdown 列表
+func (r *SearchResult) Markdown() string {
+	var b strings.Builder
+	for _, p := range r.Packages {
+		fmt.Fprintf(&b, "- %s (%s)", p.Path, p.Name)
+		if p.ImportedBy > 0 {
+			fmt.Fprintf(&b, ", imported by %d", p.ImportedBy)
+		}
+		if p.Version != "" {
+			fmt.Fprintf(&b, ", %s", p.Version)
+		}
+		if !p.Published.IsZero() {
+			fmt.Fprintf(&b, " published on %s", p.Published.Format(time.DateOnly))
+		}
+		if len(p.Licenses) > 0 {
+			fmt.Fprintf(&b, ", %s", strings.Join(p.Licenses, ", "))
+		}
+		b.WriteString("\n")
+		if p.Synopsis != "" {
+			fmt.Fprintf(&b, "  %s\n", p.Synopsis)
+		}
+		if len(p.SubPackages) > 0 {
+			fmt.Fprintf(&b, "  other packages in module: %s\n", strings.Join(p.SubPackages, ", "))
+		}
+	}
+	for _, s := range r.Symbols {
+		fmt.Fprintf(&b, "- %s.%s (%s)", s.PackagePath, s.Name, s.Kind)
+		if s.ImportedBy > 0 {
+			fmt.Fprintf(&b, ", imported by %d", s.ImportedBy)
+		}
+		b.WriteString("\n")
+		if s.Signature != "" {
+			fmt.Fprintf(&b, "  `%s`\n", s.Signature)
+		}
+	}
+	return b.String()
+}
+
+func writeDecl(b *strings.Builder, definition, comment, sourceURL string) {
+	writeCode(b, definition)
+	if comment != "" {
+		writeParagraph(b, comment)
+	}
+	if sourceURL != "" {
+		fmt.Fprintf(b, "[source](%s)\n\n", sourceURL)
+	}
+}
+
+func writeCode(b *strings.Builder, code string) {
+	if code == "" {
+		return
+	}
+	b.WriteString("```go\n")
+	b.WriteString(strings.TrimSpace(code))
+	b.WriteString("\n```\n\n")
+}
+
+func writeParagraph(b *strings.Builder, text string) {
+	b.WriteString(strings.TrimSpace(text))
+	b.WriteString("\n\n")
+}
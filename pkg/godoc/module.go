@@ -0,0 +1,114 @@
+package godoc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ModuleInfo 包所在的 module，对应 pkg.go.dev 页面头部和 Details 中的信息
+type ModuleInfo struct {
+	// Path module path，页面上没有时根据 import path 推测，标准库是 std
+	Path    string
+	Version string
+	// Published 发布的时间，未知时为零值
+	Published     time.Time `json:",omitzero"`
+	RepositoryURL string    `json:",omitempty"`
+	// PackageName package 语句中的名字，也就是 import 之后使用的名字，例如 gopkg.in/yaml.v3 是 yaml
+	PackageName string
+	// 以下对应 pkg.go.dev 的 Details 中的标记
+	ValidGoMod             bool
+	RedistributableLicense bool
+	TaggedVersion          bool
+	StableVersion          bool
+	// Deprecated go.mod 中 module 的 // Deprecated: 注释
+	Deprecated *Deprecation `json:",omitempty"`
+	// Retracted 当前版本是否被撤回，RetractRationale 是撤回的原因
+	Retracted        bool   `json:",omitempty"`
+	RetractRationale string `json:",omitempty"`
+}
+
+func extractDocModule(doc *goquery.Document, importPath, version string) *ModuleInfo {
+	info := &ModuleInfo{
+		Version: version,
+	}
+
+	info.Path = strings.TrimSpace(findFirst(doc.Selection,
+		"[data-test-id='UnitHeader-modulePath'] a",
+		".UnitMeta-module a",
+	).First().Text())
+	if info.Path == "" && importPath != "" {
+		if isStdImportPath(importPath) {
+			info.Path = "std"
+		} else {
+			info.Path = guessModulePath(importPath)
+		}
+	}
+
+	published := findFirst(doc.Selection,
+		"[data-test-id='UnitHeader-commitTime']",
+		".DetailsHeader-commitTime",
+	).First().Text()
+	published = strings.TrimSpace(published)
+	published = strings.TrimPrefix(published, "Published:")
+	info.Published = parseCommitTime(published)
+
+	info.RepositoryURL = strings.TrimSpace(findFirst(doc.Selection,
+		".UnitMeta-repo a",
+		"[data-test-id='UnitHeader-repo'] a",
+	).First().AttrOr("href", ""))
+
+	// 标题是包名，命令是 main。旧版本的标题是 package xxx
+	title := strings.TrimSpace(findFirst(doc.Selection,
+		"[data-test-id='UnitHeader-title']",
+		".UnitHeader-titleHeading",
+		".DetailsHeader-title",
+	).First().Text())
+	title = strings.TrimPrefix(title, "package ")
+	if title != "" && !strings.ContainsAny(title, " /") {
+		info.PackageName = title
+	}
+
+	// 弃用和撤回显示在页面头部的横幅中
+	if banner := findFirst(doc.Selection,
+		"[data-test-id='UnitHeader-deprecatedBanner']",
+		".UnitHeader-banner--deprecated",
+	).First(); banner.Length() > 0 {
+		message := strings.Join(strings.Fields(banner.Text()), " ")
+		if _, after, ok := strings.Cut(message, "Deprecated:"); ok {
+			message = strings.TrimSpace(after)
+		}
+		info.Deprecated = newDeprecation(message)
+	}
+	if banner := findFirst(doc.Selection,
+		"[data-test-id='UnitHeader-retractedBanner']",
+		".UnitHeader-banner--retracted",
+	).First(); banner.Length() > 0 {
+		info.Retracted = true
+		message := strings.Join(strings.Fields(banner.Text()), " ")
+		for _, sep := range []string{"Reason:", "Retracted:"} {
+			if _, after, ok := strings.Cut(message, sep); ok {
+				info.RetractRationale = strings.TrimSpace(after)
+				break
+			}
+		}
+	}
+
+	// Details 中每一项前面的图标 alt 是 checked 或者 unchecked
+	doc.Find(".UnitMeta-details li").Each(func(i int, s *goquery.Selection) {
+		checked := s.Find("img[alt='checked']").Length() > 0
+		text := strings.Join(strings.Fields(s.Text()), " ")
+		switch {
+		case strings.Contains(text, "go.mod"):
+			info.ValidGoMod = checked
+		case strings.Contains(text, "Redistributable license"):
+			info.RedistributableLicense = checked
+		case strings.Contains(text, "Tagged version"):
+			info.TaggedVersion = checked
+		case strings.Contains(text, "Stable version"):
+			info.StableVersion = checked
+		}
+	})
+	return info
+}
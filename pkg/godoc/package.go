@@ -4,17 +4,22 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/pkg/errors"
-	"github.com/yikakia/cachalot"
-	"github.com/yikakia/cachalot/core/cache"
-	"github.com/yikakia/cachalot/core/codec"
 	"go.uber.org/multierr"
 )
 
 type PackageDocument struct {
+	// Version 实际获取到的版本，请求时没有指定版本则是最新的版本。GOPATH 中的包没有版本
+	Version string
+	// Licenses 文档页面头部显示的 license 类型，例如 MIT，完整的信息使用 GetLicenses 获取
+	Licenses []string `json:",omitempty"`
+	// Module 包所在的 module 的信息
+	Module *ModuleInfo `json:",omitempty"`
+	// Deprecated 包的注释中的 Deprecated: 段落，module 的弃用和撤回在 Module 中
+	Deprecated *Deprecation `json:",omitempty"`
+	// Vulns 影响这个版本的已知漏洞，只有配置了 Config.VulnDB 才有
+	Vulns       []*Vuln `json:",omitempty"`
 	Overview    string
 	Consts      []ConstBlock
 	Variables   []VariableBlock
@@ -28,18 +33,21 @@ type ConstBlock struct {
 	SourceURL  string
 	Definition string
 	Comment    string
+	Deprecated *Deprecation `json:",omitempty"`
 }
 
 type VariableBlock struct {
 	SourceURL  string
 	Definition string
 	Comment    string
+	Deprecated *Deprecation `json:",omitempty"`
 }
 
 type FunctionBlock struct {
 	SourceURL  string
 	Definition string
 	Comment    string
+	Deprecated *Deprecation `json:",omitempty"`
 	// TODO 支持 Examples 可能需要再拿一个结构体
 	//	Examples   []string
 }
@@ -48,6 +56,7 @@ type TypeBlock struct {
 	SourceURL     string
 	Definition    string
 	Comment       string
+	Deprecated    *Deprecation `json:",omitempty"`
 	TypeFunctions []TypeFunction
 	TypeMethods   []TypeMethod
 }
@@ -56,12 +65,14 @@ type TypeFunction struct {
 	SourceURL  string
 	Definition string
 	Comment    string
+	Deprecated *Deprecation `json:",omitempty"`
 }
 
 type TypeMethod struct {
 	SourceURL  string
 	Definition string
 	Comment    string
+	Deprecated *Deprecation `json:",omitempty"`
 }
 
 type SubPackage struct {
@@ -77,65 +88,30 @@ type ExampleBlock struct {
 
 type GetPackageRequest struct {
 	PackageName string
-	NeedURL     bool
+	// Version 为空时获取最新版本。也可以写在 PackageName 里，例如 github.com/pkg/errors@v0.9.1
+	Version string
+	NeedURL bool
 }
 
-var pkgCache = sync.OnceValue(func() cache.Cache[[]byte] {
-	b, err := cachalot.NewBuilder[[]byte]("pkg", store())
-	if err != nil {
-		panic(err)
-	}
-
-	b.WithCacheMissLoader(pkgLoader).WithLogicExpireLoader(pkgLoader).WithLogicExpireBytesAdapter(true).WithCompression(codec.GzipCompressionCodec{})
-
-	build, err := b.Build()
-	if err != nil {
-		panic(err)
-	}
-
-	return build
-})
-
-func pkgLoader(ctx context.Context, pkgName string) ([]byte, error) {
-	if !strings.HasPrefix(pkgName, "getPkg") {
-		return nil, fmt.Errorf("package name must start with getPkg")
-	}
-	pkgName = strings.TrimPrefix(pkgName, "getPkg")
-	resp, err := client().
-		R().
-		Get(baseURL() + "/" + pkgName)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-	return resp.Body(), nil
-}
-
-func doGetPkg(pkgName string) ([]byte, error) {
-	pkgGet, err := pkgCache().Get(context.Background(), "getPkg"+pkgName)
-	if err != nil {
-		return nil, err
-	}
-	return pkgGet, nil
-}
-
-func GetPackageDocument(req GetPackageRequest) (*PackageDocument, error) {
-	pkgGet, err := doGetPkg(req.PackageName)
-	if err != nil {
-		return nil, err
+// pathAndVersion 拆出 import path 和版本，Version 优先于 PackageName 里的版本
+func (r GetPackageRequest) pathAndVersion() (string, string) {
+	importPath, version := splitVersion(r.PackageName)
+	if r.Version != "" {
+		version = r.Version
 	}
-
-	result, err := extractDocResult(string(pkgGet), req)
-	if err != nil {
-		return nil, err
+	// latest 和不指定版本一样
+	if version == "latest" {
+		version = ""
 	}
-	return result, nil
+	return importPath, version
 }
 
-func extractDocResult(html string, req GetPackageRequest) (*PackageDocument, error) {
+func extractDocResult(ctx context.Context, html string, req GetPackageRequest) (*PackageDocument, error) {
 	doc, err := getDoc(html)
 	if err != nil {
 		return nil, err
 	}
+	reportProgress(ctx, "parsing overview, consts, variables and functions")
 	overview, err := extractDocOverview(doc, req)
 	if err != nil {
 		return nil, err
@@ -152,10 +128,11 @@ func extractDocResult(html string, req GetPackageRequest) (*PackageDocument, err
 	if err != nil {
 		return nil, err
 	}
-	types, err := extractDocTypes(doc, req)
+	types, err := extractDocTypes(ctx, doc, req)
 	if err != nil {
 		return nil, err
 	}
+	reportProgress(ctx, "parsing subpackages and examples")
 	subPackages, err := extractSubPackages(doc, req)
 	if err != nil {
 		return nil, err
@@ -165,7 +142,21 @@ func extractDocResult(html string, req GetPackageRequest) (*PackageDocument, err
 		return nil, err
 	}
 
-	return &PackageDocument{
+	importPath, version := req.pathAndVersion()
+	if v := extractDocVersion(doc); v != "" {
+		version = v
+	}
+
+	// overview 的段落被拼在一起了，单独找 Deprecated: 段落
+	paragraphs := doc.Find(".Documentation-overview p").Map(func(i int, s *goquery.Selection) string {
+		return strings.TrimSpace(s.Text())
+	})
+
+	result := &PackageDocument{
+		Version:     version,
+		Licenses:    extractDocLicenses(doc),
+		Module:      extractDocModule(doc, importPath, version),
+		Deprecated:  parseDeprecation(strings.Join(paragraphs, "\n\n")),
 		Overview:    overview,
 		Consts:      consts,
 		Variables:   variables,
@@ -173,20 +164,34 @@ func extractDocResult(html string, req GetPackageRequest) (*PackageDocument, err
 		Types:       types,
 		SubPackages: subPackages,
 		Examples:    examples,
-	}, nil
+	}
+	markDeprecations(result)
+	return result, nil
+}
+
+// extractDocVersion 页面头部显示的版本，例如 Version: v1.2.3
+func extractDocVersion(doc *goquery.Document) string {
+	version := findFirst(doc.Selection,
+		"[data-test-id='UnitHeader-version'] a",
+		".DetailsHeader-version",
+	).First().Text()
+	version = strings.TrimSpace(version)
+	version = strings.TrimSpace(strings.TrimPrefix(version, "Version:"))
+	return version
 }
 
 func extractDocOverview(doc *goquery.Document, req GetPackageRequest) (string, error) {
 	var overview string
 
-	overview = doc.Find("section.Documentation-overview p").Text()
+	// 新版本的 pkgsite 用 section，旧版本用 div，所以 selector 里都只用 class
+	overview = doc.Find(".Documentation-overview p").Text()
 	return overview, nil
 }
 
 func extractDocConsts(doc *goquery.Document, req GetPackageRequest) ([]ConstBlock, error) {
 	var consts []ConstBlock
 	doc.
-		Find("section.Documentation-constants").
+		Find(".Documentation-constants").
 		Children().
 		Each(func(i int, s *goquery.Selection) {
 			// 如果现在是 div 标签，则是常量定义
@@ -224,7 +229,7 @@ func extractDocConsts(doc *goquery.Document, req GetPackageRequest) ([]ConstBloc
 func extractDocVariables(doc *goquery.Document, req GetPackageRequest) ([]VariableBlock, error) {
 	var vars []VariableBlock
 	doc.
-		Find("section.Documentation-variables").
+		Find(".Documentation-variables").
 		Children().
 		Each(func(i int, s *goquery.Selection) {
 			if s.Is("div") && s.AttrOr("class", "") == "Documentation-declaration" {
@@ -261,7 +266,7 @@ func extractDocVariables(doc *goquery.Document, req GetPackageRequest) ([]Variab
 func extractDocFunctions(doc *goquery.Document, req GetPackageRequest) ([]FunctionBlock, error) {
 	var fns []FunctionBlock
 	doc.
-		Find("section.Documentation-functions").
+		Find(".Documentation-functions").
 		// 和前面的不一样，函数都被 div.Documentation-function 包裹了
 		Find("div.Documentation-function").
 		Each(func(i int, s *goquery.Selection) {
@@ -289,21 +294,24 @@ func extractDocFunctions(doc *goquery.Document, req GetPackageRequest) ([]Functi
 	return fns, nil
 }
 
-func extractDocTypes(doc *goquery.Document, req GetPackageRequest) ([]TypeBlock, error) {
+func extractDocTypes(ctx context.Context, doc *goquery.Document, req GetPackageRequest) ([]TypeBlock, error) {
 	var types []TypeBlock
 	var err error
-	doc.
-		Find("section.Documentation-types").
+	typeNodes := doc.
+		Find(".Documentation-types").
 		// type 被 div.Documentation-type 包裹了
-		Find("div.Documentation-type").
+		Find("div.Documentation-type")
+	total := typeNodes.Length()
+	typeNodes.
 		Each(func(i int, s *goquery.Selection) {
+			reportProgress(ctx, "parsing types (%d/%d)", i+1, total)
 			tpb := TypeBlock{}
 			if req.NeedURL {
-				// 找到 h4 标签 Documentation-typeHeader
+				// 找到 Documentation-typeHeader，不同版本的标签不一样
 				// 找到 a 标签 Documentation-source
 				// 这是类型的链接
 				tpb.SourceURL = s.
-					Find("h4.Documentation-typeHeader").
+					Find(".Documentation-typeHeader").
 					Find("a.Documentation-source").
 					AttrOr("href", "")
 			}
@@ -347,7 +355,7 @@ func extractDocTypeFunctions(s *goquery.Selection, req GetPackageRequest) ([]Typ
 			if req.NeedURL {
 				// url
 				fnb.SourceURL = s.
-					Find("h4.Documentation-typeFuncHeader").
+					Find(".Documentation-typeFuncHeader").
 					Find("a.Documentation-source").
 					AttrOr("href", "")
 			}
@@ -373,7 +381,7 @@ func extractDocTypeMethods(s *goquery.Selection, req GetPackageRequest) ([]TypeM
 			if req.NeedURL {
 				// url
 				method.SourceURL = s.
-					Find("h4.Documentation-typeMethodHeader").
+					Find(".Documentation-typeMethodHeader").
 					Find("a.Documentation-source").
 					AttrOr("href", "")
 			}
@@ -432,7 +440,7 @@ func extractDeclarationText(s *goquery.Selection) string {
 func extractDocExamples(doc *goquery.Document) ([]ExampleBlock, error) {
 	var examples []ExampleBlock
 
-	doc.Find("section.Documentation-examples a.js-exampleHref").
+	doc.Find(".Documentation-examples a.js-exampleHref").
 		Each(func(i int, s *goquery.Selection) {
 			name := strings.TrimSpace(s.Text())
 			href := strings.TrimSpace(s.AttrOr("href", ""))
@@ -464,7 +472,11 @@ func extractSubPackages(doc *goquery.Document, req GetPackageRequest) ([]*SubPac
 	var subPackages []*SubPackage
 	var err error
 
-	doc.Find("table[data-test-id='UnitDirectories-table']").
+	findFirst(doc.Selection,
+		"table[data-test-id='UnitDirectories-table']",
+		"table.UnitDirectories-table",
+		".UnitDirectories table",
+	).
 		Find("tr").
 		Each(func(i int, s *goquery.Selection) {
 			if s.HasClass("UnitDirectories-tableHeader") {
@@ -0,0 +1,213 @@
+package godoc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+	"github.com/yikakia/cachalot/core/cache"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+)
+
+// PkgsiteConfig pkg.go.dev 或者兼容的 pkgsite 的配置
+type PkgsiteConfig struct {
+	// Name 区分不同的 pkgsite，同时作为缓存 key 的前缀
+	Name    string
+	BaseURL string
+	Client  ClientConfig
+	// Cache 只使用其中的 TTL 和 MaxTTL，缓存的大小由 store 决定
+	Cache CacheConfig
+}
+
+// PkgsiteProvider 从 pkgsite 的网页中抓取文档
+type PkgsiteProvider struct {
+	name        string
+	baseURL     string
+	client      *resty.Client
+	pkgCache    *pageCache
+	searchCache *pageCache
+}
+
+// NewPkgsiteProvider 页面会缓存在 store 里，多个 PkgsiteProvider 可以共用一个 store
+func NewPkgsiteProvider(cfg PkgsiteConfig, store cache.Store) (*PkgsiteProvider, error) {
+	if cfg.BaseURL == "" {
+		return nil, errors.Errorf("pkgsite %s: baseURL is required", cfg.Name)
+	}
+	client, err := newClient(cfg.Client)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "pkgsite %s", cfg.Name)
+	}
+	p := &PkgsiteProvider{
+		name:    cfg.Name,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		client:  client,
+	}
+
+	p.pkgCache, err = newPageCache(p.name+"-pkg", store, cfg.Cache, cfg.Client.Timeout, p.pkgLoader)
+	if err != nil {
+		return nil, err
+	}
+	p.searchCache, err = newPageCache(p.name+"-search", store, cfg.Cache, cfg.Client.Timeout, p.searchLoader)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *PkgsiteProvider) Name() string {
+	return p.name
+}
+
+// cacheKey 不同的 pkgsite 共用一个 store，所以 key 里带上名字
+func (p *PkgsiteProvider) cacheKey(kind, key string) string {
+	return p.name + "/" + kind + key
+}
+
+func (p *PkgsiteProvider) trimCacheKey(kind, key string) (string, error) {
+	prefix := p.name + "/" + kind
+	if !strings.HasPrefix(key, prefix) {
+		return "", fmt.Errorf("cache key must start with %s", prefix)
+	}
+	return strings.TrimPrefix(key, prefix), nil
+}
+
+func (p *PkgsiteProvider) pkgLoader(ctx context.Context, key string) ([]byte, error) {
+	pkgName, err := p.trimCacheKey("getPkg", key)
+	if err != nil {
+		return nil, err
+	}
+	reportFetching(ctx, p.baseURL)
+	resp, err := p.client.
+		R().
+		SetContext(ctx).
+		Get(p.baseURL + "/" + pkgName)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "get package from upstream failed", "package", pkgName, "err", err)
+		return nil, errors.WithStack(err)
+	}
+	logResponse(ctx, resp)
+	if !resp.IsSuccess() {
+		return nil, errors.Errorf("get package %s from upstream failed, status: %s", pkgName, resp.Status())
+	}
+	return resp.Body(), nil
+}
+
+func (p *PkgsiteProvider) searchLoader(ctx context.Context, key string) ([]byte, error) {
+	rawQuery, err := p.trimCacheKey("search", key)
+	if err != nil {
+		return nil, err
+	}
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	q := params.Get("q")
+	reportFetching(ctx, p.baseURL)
+
+	resp, err := p.client.R().
+		SetContext(ctx).
+		SetQueryParamsFromValues(params).
+		Get(p.baseURL + "/search")
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "search upstream failed", "query", q, "err", err)
+		return nil, errors.WithStack(err)
+	}
+	logResponse(ctx, resp)
+	if !resp.IsSuccess() {
+		return nil, errors.Errorf("search %s from upstream failed, status: %s", q, resp.Status())
+	}
+	return resp.Body(), nil
+}
+
+// getPage 获取页面，优先使用缓存
+func (p *PkgsiteProvider) getPage(ctx context.Context, c *pageCache, kind, key string) ([]byte, error) {
+	return c.get(ctx, p.cacheKey(kind, key))
+}
+
+func (p *PkgsiteProvider) Search(ctx context.Context, req SearchRequest) (*SearchResult, error) {
+	// 缓存 key 就是请求的参数，不同的模式和每一页分开缓存
+	params := url.Values{
+		"q": {req.Query},
+		"m": {req.mode()},
+	}
+	if req.Limit > 0 {
+		params.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if req.Page > 1 {
+		params.Set("page", strconv.Itoa(req.Page))
+	}
+	page, err := p.getPage(ctx, p.searchCache, "search", params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	reportProgress(ctx, "parsing search results")
+	if req.mode() == SearchModeSymbol {
+		return extractSymbolSearchResult(ctx, string(page), p.baseURL, req)
+	}
+	return extractSearchResult(ctx, string(page), p.baseURL, req)
+}
+
+func (p *PkgsiteProvider) GetPackageDocument(ctx context.Context, req GetPackageRequest) (*PackageDocument, error) {
+	// 不同版本分开缓存
+	importPath, version := req.pathAndVersion()
+	if version != "" {
+		importPath += "@" + version
+	}
+	page, err := p.getPage(ctx, p.pkgCache, "getPkg", importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := extractDocResult(ctx, string(page), req)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "extract package document failed", "package", importPath, "err", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (p *PkgsiteProvider) ListVersions(ctx context.Context, importPath string) (*VersionList, error) {
+	importPath, _ = splitVersion(importPath)
+	page, err := p.getPage(ctx, p.pkgCache, "getPkg", importPath+"?tab=versions")
+	if err != nil {
+		return nil, err
+	}
+
+	reportProgress(ctx, "parsing versions")
+	return extractVersionList(string(page))
+}
+
+func (p *PkgsiteProvider) GetLicenses(ctx context.Context, importPath string) (*LicenseList, error) {
+	page, err := p.getPage(ctx, p.pkgCache, "getPkg", importPath+"?tab=licenses")
+	if err != nil {
+		return nil, err
+	}
+
+	reportProgress(ctx, "parsing licenses")
+	return extractLicenseList(string(page))
+}
+
+func (p *PkgsiteProvider) GetImports(ctx context.Context, importPath string) ([]ImportRef, error) {
+	page, err := p.getPage(ctx, p.pkgCache, "getPkg", importPath+"?tab=imports")
+	if err != nil {
+		return nil, err
+	}
+
+	reportProgress(ctx, "parsing imports")
+	return extractImportRefs(string(page), ".Imports", ".UnitImports")
+}
+
+func (p *PkgsiteProvider) GetImportedBy(ctx context.Context, importPath string) ([]ImportRef, error) {
+	page, err := p.getPage(ctx, p.pkgCache, "getPkg", importPath+"?tab=importedby")
+	if err != nil {
+		return nil, err
+	}
+
+	reportProgress(ctx, "parsing imported by")
+	return extractImportRefs(string(page), ".ImportedBy", ".UnitImportedBy")
+}
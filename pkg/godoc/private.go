@@ -0,0 +1,87 @@
+package godoc
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// privatePatterns 合并 GOPRIVATE, GONOPROXY, GONOSUMDB，cfg 中为空的使用 go env 的值
+func privatePatterns(cfg PrivateConfig) string {
+	values := map[string]string{
+		"GOPRIVATE": cfg.GOPRIVATE,
+		"GONOPROXY": cfg.GONOPROXY,
+		"GONOSUMDB": cfg.GONOSUMDB,
+	}
+	var missing []string
+	for k, v := range values {
+		if v == "" {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		fromGo, err := goEnvValues(missing...)
+		for _, k := range missing {
+			if err == nil {
+				values[k] = fromGo[k]
+			} else {
+				values[k] = os.Getenv(k)
+			}
+		}
+	}
+
+	// GONOPROXY 和 GONOSUMDB 默认就是 GOPRIVATE，去掉重复的
+	var patterns []string
+	for _, k := range []string{"GOPRIVATE", "GONOPROXY", "GONOSUMDB"} {
+		for _, pattern := range strings.Split(values[k], ",") {
+			pattern = strings.TrimSpace(pattern)
+			if pattern != "" && !slices.Contains(patterns, pattern) {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return strings.Join(patterns, ",")
+}
+
+// privateProvider 放在私有 import path 的路由的最后，没有其他来源或者都失败时返回明确的错误，
+// 而不是把请求发给公开的 pkgsite
+type privateProvider struct {
+	patterns string
+}
+
+func (p privateProvider) Name() string {
+	return "private"
+}
+
+func (p privateProvider) err(importPath string) error {
+	importPath, _ = splitVersion(importPath)
+	return errors.Errorf("%s matches GOPRIVATE/GONOPROXY/GONOSUMDB (%s) and is never sent to the public pkgsite, "+
+		"add a pkgsite for it to godoc.pkgsites or use the local or auto backend", importPath, p.patterns)
+}
+
+func (p privateProvider) Search(ctx context.Context, req SearchRequest) (*SearchResult, error) {
+	return nil, p.err(strings.TrimSpace(req.Query))
+}
+
+func (p privateProvider) GetPackageDocument(ctx context.Context, req GetPackageRequest) (*PackageDocument, error) {
+	return nil, p.err(req.PackageName)
+}
+
+func (p privateProvider) ListVersions(ctx context.Context, importPath string) (*VersionList, error) {
+	return nil, p.err(importPath)
+}
+
+func (p privateProvider) GetLicenses(ctx context.Context, importPath string) (*LicenseList, error) {
+	return nil, p.err(importPath)
+}
+
+func (p privateProvider) GetImports(ctx context.Context, importPath string) ([]ImportRef, error) {
+	return nil, p.err(importPath)
+}
+
+func (p privateProvider) GetImportedBy(ctx context.Context, importPath string) ([]ImportRef, error) {
+	return nil, p.err(importPath)
+}
@@ -0,0 +1,52 @@
+package godoc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ProgressFunc 接收处理的进度，progress 每次都会增加，total 为 0 表示总量未知
+type ProgressFunc func(progress, total float64, message string)
+
+type progressKey struct{}
+
+type progressReporter struct {
+	mu   sync.Mutex
+	fn   ProgressFunc
+	step float64
+}
+
+// WithProgress 返回带有进度回调的 ctx，把它传给 Search/GetPackageDocument 就可以收到
+// "fetching from upstream"、"parsing types (n/m)"、"served from cache" 之类的进度
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, &progressReporter{fn: fn})
+}
+
+func getProgressReporter(ctx context.Context) *progressReporter {
+	r, _ := ctx.Value(progressKey{}).(*progressReporter)
+	return r
+}
+
+// reportProgress 上报一步进度，ctx 里没有进度回调时什么都不做
+func reportProgress(ctx context.Context, format string, args ...any) {
+	r := getProgressReporter(ctx)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.step++
+	step := r.step
+	r.mu.Unlock()
+	r.fn(step, 0, fmt.Sprintf(format, args...))
+}
+
+// reportFetching 在回源时调用
+func reportFetching(ctx context.Context, source string) {
+	reportProgress(ctx, "fetching from %s", source)
+}
+
+// reportCacheHit 只在结果确实来自缓存时调用，等待其他请求回源的调用方不算
+func reportCacheHit(ctx context.Context) {
+	reportProgress(ctx, "served from cache")
+}
@@ -0,0 +1,267 @@
+package godoc
+
+import (
+	"cmp"
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotSupported Provider 不支持某个操作时返回，CompositeProvider 遇到它会尝试下一个 Provider
+var ErrNotSupported = errors.New("not supported by this provider")
+
+// Provider 文档的来源，例如 pkg.go.dev、自建的 pkgsite 或者本地的源码
+type Provider interface {
+	// Name 用于日志和错误信息
+	Name() string
+	Search(ctx context.Context, req SearchRequest) (*SearchResult, error)
+	GetPackageDocument(ctx context.Context, req GetPackageRequest) (*PackageDocument, error)
+	// ListVersions 列出 import path 所在 module 的版本
+	ListVersions(ctx context.Context, importPath string) (*VersionList, error)
+	// GetImports 列出包导入的包，importPath 可以带版本
+	GetImports(ctx context.Context, importPath string) ([]ImportRef, error)
+	// GetImportedBy 列出导入了这个包的包
+	GetImportedBy(ctx context.Context, importPath string) ([]ImportRef, error)
+	// GetLicenses 获取包所在 module 的 license
+	GetLicenses(ctx context.Context, importPath string) (*LicenseList, error)
+}
+
+const (
+	// SearchModePackage 搜索包，是默认的模式
+	SearchModePackage = "package"
+	// SearchModeSymbol 搜索函数、类型等符号，例如 ParseDuration
+	SearchModeSymbol = "symbol"
+)
+
+type SearchRequest struct {
+	Query string
+	// Mode SearchModePackage 或者 SearchModeSymbol，为空时使用 SearchModePackage
+	// Query 以 # 开头时总是搜索符号，和 pkg.go.dev 一样
+	Mode string
+	// Limit 每页的数量，0 时使用 pkg.go.dev 的默认值，最大是 maxSearchLimit
+	Limit int
+	// Page 从 1 开始，0 等同于 1
+	Page int
+	// Filter 和 Rerank 在获取结果之后处理，不影响缓存
+	Filter SearchFilter
+	// Rerank 结合 ImportedBy 和发布时间重新排序当前页的包
+	Rerank bool
+}
+
+// mode 返回实际使用的模式
+func (r SearchRequest) mode() string {
+	if strings.HasPrefix(strings.TrimSpace(r.Query), "#") {
+		return SearchModeSymbol
+	}
+	if r.Mode == "" {
+		return SearchModePackage
+	}
+	return r.Mode
+}
+
+var (
+	providerMu sync.Mutex
+	provider   Provider
+)
+
+// SetProvider 替换 Search/GetPackageDocument/ListVersions 使用的 Provider
+// 不调用时会在第一次使用时根据 Config 创建
+func SetProvider(p Provider) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	provider = p
+}
+
+// DefaultProvider 返回当前使用的 Provider
+func DefaultProvider() (Provider, error) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	if provider != nil {
+		return provider, nil
+	}
+	p, err := NewProviderFromConfig(getConfig())
+	if err != nil {
+		return nil, err
+	}
+	provider = p
+	return provider, nil
+}
+
+// NewProviderFromConfig 根据 Config.Backend 创建 Provider
+func NewProviderFromConfig(cfg Config) (Provider, error) {
+	var pkgsite, local Provider
+	newPkgsite := func() (Provider, error) {
+		if pkgsite != nil {
+			return pkgsite, nil
+		}
+		p, err := NewPkgsiteProvider(PkgsiteConfig{
+			Name:    "pkgsite",
+			BaseURL: cfg.BaseURL,
+			Client: ClientConfig{
+				Timeout:     cfg.Timeout,
+				Proxy:       cfg.Proxy,
+				UserAgent:   cfg.UserAgent,
+				Headers:     cfg.Headers,
+				BearerToken: cfg.BearerToken,
+				TLS:         cfg.TLS,
+			},
+			Cache: cfg.Cache,
+		}, store())
+		pkgsite = p
+		return p, err
+	}
+	newLocal := func() Provider {
+		if local == nil {
+			local = NewLocalProvider(cfg.Local)
+		}
+		return local
+	}
+
+	switch cfg.Backend {
+	case BackendPkgsite:
+		p, err := newPkgsite()
+		if err != nil {
+			return nil, err
+		}
+		routes, err := newPkgsiteRoutes(cfg)
+		if err != nil {
+			return nil, err
+		}
+		routes = appendPrivateRoute(routes, cfg)
+		if len(routes) == 0 {
+			return p, nil
+		}
+		return NewCompositeProvider(routes, p), nil
+	case BackendLocal:
+		return newLocal(), nil
+	case BackendAuto:
+		p, err := newPkgsite()
+		if err != nil {
+			return nil, err
+		}
+		routes, err := newPkgsiteRoutes(cfg, newLocal())
+		if err != nil {
+			return nil, err
+		}
+		routes = appendPrivateRoute(routes, cfg, newLocal())
+		return NewCompositeProvider(routes, p, newLocal()), nil
+	default:
+		return nil, errors.Errorf("unknown backend %q", cfg.Backend)
+	}
+}
+
+// appendPrivateRoute 私有的 import path 只使用 providers，不会发给 BaseURL
+// 放在 Pkgsites 的路由之后，这样自建的 pkgsite 优先
+func appendPrivateRoute(routes []Route, cfg Config, providers ...Provider) []Route {
+	patterns := privatePatterns(cfg.Private)
+	if patterns == "" {
+		return routes
+	}
+	return append(routes, Route{
+		Patterns:  patterns,
+		Providers: append(providers, privateProvider{patterns: patterns}),
+	})
+}
+
+// newPkgsiteRoutes 为 Config.Pkgsites 创建路由，fallback 在对应的 pkgsite 失败后使用
+func newPkgsiteRoutes(cfg Config, fallback ...Provider) ([]Route, error) {
+	var routes []Route
+	// pkgsite 是默认站点的名字
+	names := map[string]bool{"pkgsite": true}
+	for _, src := range cfg.Pkgsites {
+		name := src.Name
+		if name == "" {
+			u, err := url.Parse(src.BaseURL)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse pkgsite baseURL %q", src.BaseURL)
+			}
+			name = u.Host
+		}
+		if names[name] {
+			return nil, errors.Errorf("duplicate pkgsite name %q", name)
+		}
+		names[name] = true
+		if src.Patterns == "" {
+			return nil, errors.Errorf("pkgsite %s: patterns is required", name)
+		}
+
+		p, err := NewPkgsiteProvider(PkgsiteConfig{
+			Name:    name,
+			BaseURL: src.BaseURL,
+			Client: ClientConfig{
+				Timeout:     cmp.Or(src.Timeout, cfg.Timeout),
+				Proxy:       cmp.Or(src.Proxy, cfg.Proxy),
+				UserAgent:   cmp.Or(src.UserAgent, cfg.UserAgent),
+				Headers:     src.Headers,
+				BearerToken: src.BearerToken,
+				TLS:         src.TLS,
+			},
+			Cache: cfg.Cache,
+		}, store())
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, Route{
+			Patterns:  src.Patterns,
+			Providers: append([]Provider{p}, fallback...),
+		})
+	}
+	return routes, nil
+}
+
+// Search 搜索包，ctx 取消时立即返回，没有其他请求在等待同一个页面时也会中断对上游的请求
+func Search(ctx context.Context, req SearchRequest) (*SearchResult, error) {
+	switch req.mode() {
+	case SearchModePackage, SearchModeSymbol:
+	default:
+		return nil, errors.Errorf("unknown search mode %q, must be %s or %s", req.Mode, SearchModePackage, SearchModeSymbol)
+	}
+	if req.Limit < 0 || req.Limit > maxSearchLimit {
+		return nil, errors.Errorf("limit must be between 0 and %d", maxSearchLimit)
+	}
+	if req.Page < 0 {
+		return nil, errors.New("page must not be negative")
+	}
+	p, err := DefaultProvider()
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result.Filtered = filterSearchResult(result, req.Filter)
+	if req.Rerank {
+		rerankSearchResult(result, time.Now())
+	}
+	recordSearchResult(result)
+	return result, nil
+}
+
+// GetPackageDocument 获取包的文档，ctx 取消的行为和 Search 一样
+func GetPackageDocument(ctx context.Context, req GetPackageRequest) (*PackageDocument, error) {
+	p, err := DefaultProvider()
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.GetPackageDocument(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	recordPackageDocument(req.PackageName, result)
+	importPath, _ := req.pathAndVersion()
+	return attachVulns(ctx, importPath, result), nil
+}
+
+// ListVersions 列出 import path 所在 module 的版本
+func ListVersions(ctx context.Context, importPath string) (*VersionList, error) {
+	p, err := DefaultProvider()
+	if err != nil {
+		return nil, err
+	}
+	return p.ListVersions(ctx, importPath)
+}
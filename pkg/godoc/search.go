@@ -1,88 +1,61 @@
 package godoc
 
 import (
+	"cmp"
 	"context"
-	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/pkg/errors"
-	"github.com/yikakia/cachalot"
-	"github.com/yikakia/cachalot/core/cache"
-	"github.com/yikakia/cachalot/core/codec"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
 	"go.uber.org/multierr"
+	"golang.org/x/mod/semver"
 	"golang.org/x/net/html"
 )
 
+const (
+	// defaultSearchLimit pkg.go.dev 默认每页的数量
+	defaultSearchLimit = 25
+	// maxSearchLimit pkg.go.dev 每页最多的数量
+	maxSearchLimit = 100
+)
+
 type SearchResult struct {
 	Packages []*SearchPackageInfo
+	// Symbols 只有搜索符号时才有
+	Symbols []*SymbolSearchResult `json:",omitempty"`
+	// Total 所有页的结果数量，页面上没有时为 0。数量很多时 pkg.go.dev 给出的是估计值
+	Total int `json:",omitempty"`
+	// NextPage 下一页的页码，0 表示没有下一页
+	NextPage int `json:",omitempty"`
+	// Filtered 当前页被 SearchFilter 过滤掉的数量
+	Filtered int `json:",omitempty"`
 }
 
 type SearchPackageInfo struct {
-	Name        string
-	Path        string
-	Synopsis    string
-	GoDocUrl    string
-	ImportedBy  int
+	Name       string
+	Path       string
+	Synopsis   string
+	GoDocUrl   string
+	ImportedBy int
+	// Version 最新的版本，标准库是 go 的版本，例如 go1.24.1
+	Version string `json:",omitempty"`
+	// Published Version 发布的时间，未知时为零值
+	Published time.Time `json:",omitzero"`
+	// Licenses license 的类型，例如 MIT
+	Licenses []string `json:",omitempty"`
+	// Module 包所在的 module，页面上没有时根据 import path 推测，标准库是 std
+	Module      string
 	SubPackages []string `json:"sub_packages,omitempty"`
 }
 
-var searchCache = sync.OnceValue(func() cache.Cache[[]byte] {
-	b, err := cachalot.NewBuilder[[]byte]("search", store())
-	if err != nil {
-		panic(err)
-	}
-
-	b.WithCacheMissLoader(searchLoader).WithLogicExpireLoader(searchLoader).WithLogicExpireBytesAdapter(true).WithCompression(codec.GzipCompressionCodec{})
-
-	build, err := b.Build()
-	if err != nil {
-		panic(err)
-	}
-
-	return build
-})
-
-func searchLoader(ctx context.Context, q string) ([]byte, error) {
-	if !strings.HasPrefix(q, "search") {
-		return nil, fmt.Errorf("search query must start with 'search'")
-	}
-
-	q = strings.TrimPrefix(q, "search")
-
-	resp, err := client().R().
-		SetQueryParams(map[string]string{
-			"q": q,
-			"m": "package",
-		}).
-		Get(baseURL() + "/search")
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-	return resp.Body(), nil
-}
-
-func doSearch(q string) ([]byte, error) {
-	get, err := searchCache().Get(context.Background(), "search"+q)
-	if err != nil {
-		return nil, err
-	}
-	return get, nil
-}
-
-func Search(query string) (*SearchResult, error) {
-	cacheGet, err := doSearch(query)
-	if err != nil {
-		return nil, err
-	}
-
-	return extractSearchResult(string(cacheGet))
-}
-
-func extractSearchResult(query string) (*SearchResult, error) {
-	doc, err := getDoc(query)
+func extractSearchResult(ctx context.Context, html string, baseURL string, req SearchRequest) (*SearchResult, error) {
+	doc, err := getDoc(html)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +63,7 @@ func extractSearchResult(query string) (*SearchResult, error) {
 	var infos []*SearchPackageInfo
 
 	doc.Find(".SearchSnippet").Each(func(i int, selection *goquery.Selection) {
-		info, _err := extractPackageInfo(selection)
+		info, _err := extractPackageInfo(ctx, selection, baseURL)
 		if _err != nil {
 			err = multierr.Append(err, _err)
 			return
@@ -98,13 +71,47 @@ func extractSearchResult(query string) (*SearchResult, error) {
 		infos = append(infos, info)
 	})
 	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "extract search result failed", "err", err)
 		return nil, err
 	}
+	if len(infos) == 0 {
+		logging.FromContext(ctx).DebugContext(ctx, "no search snippet found in page")
+	}
+
+	result := &SearchResult{Packages: infos}
+	result.Total, result.NextPage = extractSearchPagination(doc, req, len(infos))
+	return result, nil
+}
+
+var searchTotalPattern = regexp.MustCompile(`of\s+(?:about\s+)?([\d,]+)`)
+
+// extractSearchPagination 从 "1 – 25 of 1,234 results" 中得到总数，从翻页链接或者总数得到下一页
+func extractSearchPagination(doc *goquery.Document, req SearchRequest, n int) (total, nextPage int) {
+	summary := findFirst(doc.Selection,
+		".SearchResults-summary",
+		"[data-test-id='search-results-summary']",
+	).First().Text()
+	if m := searchTotalPattern.FindStringSubmatch(strings.ToLower(summary)); m != nil {
+		total, _ = strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+	}
+	if n == 0 {
+		return total, 0
+	}
 
-	return &SearchResult{Packages: infos}, nil
+	next := doc.Find(".Pagination-next").First()
+	if href, ok := next.Attr("href"); ok && next.AttrOr("aria-disabled", "") != "true" {
+		if u, err := url.Parse(href); err == nil {
+			nextPage, _ = strconv.Atoi(u.Query().Get("page"))
+		}
+	}
+	page := max(req.Page, 1)
+	if nextPage == 0 && total > page*cmp.Or(req.Limit, defaultSearchLimit) {
+		nextPage = page + 1
+	}
+	return total, nextPage
 }
 
-func extractPackageInfo(selection *goquery.Selection) (*SearchPackageInfo, error) {
+func extractPackageInfo(ctx context.Context, selection *goquery.Selection, baseURL string) (*SearchPackageInfo, error) {
 	name, err := extractPackageName(selection)
 	if err != nil {
 		return nil, err
@@ -121,7 +128,7 @@ func extractPackageInfo(selection *goquery.Selection) (*SearchPackageInfo, error
 	if err != nil {
 		return nil, err
 	}
-	imptBy, err := extractImportedBy(selection)
+	imptBy, err := extractImportedBy(ctx, selection)
 	if err != nil {
 		return nil, err
 	}
@@ -130,64 +137,110 @@ func extractPackageInfo(selection *goquery.Selection) (*SearchPackageInfo, error
 	if err != nil {
 		return nil, err
 	}
+	licenses := splitLicenseTypes(selection.Find("[data-test-id='snippet-license'] a").First().Text())
+	version, published := extractSnippetVersion(selection)
 
 	return &SearchPackageInfo{
 		Name:        name,
 		Path:        path,
 		Synopsis:    synopsis,
-		GoDocUrl:    baseURL() + url,
+		GoDocUrl:    baseURL + url,
 		SubPackages: otherPackages,
 		ImportedBy:  imptBy,
+		Version:     version,
+		Published:   published,
+		Licenses:    licenses,
+		Module:      newImportRef(path, snippetModule(selection)).Module,
 	}, nil
 }
 
+// extractSnippetVersion 旧版本的页面没有 data-test-id，版本和日期是 infoLabel 中的 strong
+func extractSnippetVersion(selection *goquery.Selection) (string, time.Time) {
+	version := strings.TrimSpace(selection.Find("[data-test-id='snippet-version']").First().Text())
+	published := parseCommitTime(selection.Find("[data-test-id='snippet-published']").First().Text())
+	selection.Find(".SearchSnippet-infoLabel strong").Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if version == "" && (semver.IsValid(text) || strings.HasPrefix(text, "go1")) {
+			version = text
+		}
+		if published.IsZero() {
+			published = parseCommitTime(text)
+		}
+	})
+	return version, published
+}
+
+// snippetModule 从 "Other packages in module xxx:" 中得到 module，只有 module 中有多个包时才有
+func snippetModule(s *goquery.Selection) string {
+	tmp := s.Find("div.SearchSnippet-sub.go-textSubtle").
+		Find("strong").Text()
+	tmp = strings.TrimPrefix(tmp, "Other packages in module")
+	tmp = strings.Trim(tmp, ":")
+	return strings.TrimSpace(tmp)
+}
+
 func extractPackageName(selection *goquery.Selection) (string, error) {
 	var name string
-	name = selection.
-		Find("a[data-test-id='snippet-title']").
+	name = findSnippetTitle(selection).
 		Contents().Not("span").Text()
 
 	name = strings.TrimSpace(name)
+	// 旧版本的标题是完整的 import path
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
 	return name, nil
 }
 
 func extractPackagePath(selection *goquery.Selection) (string, error) {
 	var path string
-	path = selection.
-		Find("a[data-test-id='snippet-title']").
-		Find(".SearchSnippet-header-path").
-		Text()
-
+	title := findSnippetTitle(selection)
+	path = title.Find(".SearchSnippet-header-path").Text()
 	path = strings.TrimSpace(path)
 	path = strings.Trim(path, "()")
+	if path == "" {
+		// 旧版本的标题只有 import path，没有单独的 path
+		path = strings.TrimSpace(title.Text())
+	}
 	return path, nil
 }
 
 func extractPackageSynopsis(selection *goquery.Selection) (string, error) {
 	var synopsis string
-	synopsis = selection.Find("p[data-test-id='snippet-synopsis']").Text()
+	synopsis = findFirst(selection,
+		"p[data-test-id='snippet-synopsis']",
+		".SearchSnippet-synopsis",
+	).Text()
 
 	synopsis = strings.TrimSpace(synopsis)
 	return synopsis, nil
 }
 
-func extractImportedBy(selection *goquery.Selection) (int, error) {
+func extractImportedBy(ctx context.Context, selection *goquery.Selection) (int, error) {
 
-	im := selection.
-		Find("div.SearchSnippet-infoLabel").
-		Find("a[aria-label='Go to Imported By']").
-		Find("strong").Text()
+	im := findFirst(selection.Find(".SearchSnippet-infoLabel"),
+		"a[aria-label='Go to Imported By'] strong",
+		"a[href*='tab=importedby'] strong",
+	).First().Text()
 
-	atoi, _ := strconv.Atoi(im)
+	// 数字有千分位，例如 20,000
+	im = strings.ReplaceAll(strings.TrimSpace(im), ",", "")
+	if im == "" {
+		return 0, nil
+	}
+	atoi, err := strconv.Atoi(im)
+	if err != nil {
+		// 解析失败不影响其他字段
+		logging.FromContext(ctx).WarnContext(ctx, "parse imported by count failed", "text", im, "err", err)
+		return 0, nil
+	}
 	return atoi, nil
 }
 
 func extractPackageGoDocUrl(selection *goquery.Selection) (string, error) {
 	var goDocUrl string
 
-	goDocUrl, _ = selection.
-		Find("a[data-test-id='snippet-title']").
-		Attr("href")
+	goDocUrl, _ = findSnippetTitle(selection).Attr("href")
 
 	goDocUrl = strings.TrimSpace(goDocUrl)
 	return goDocUrl, nil
@@ -195,12 +248,7 @@ func extractPackageGoDocUrl(selection *goquery.Selection) (string, error) {
 
 func extractOtherPackages(s *goquery.Selection) ([]string, error) {
 	var otherPackages []string
-	var moduleName string
-	tmp := s.Find("div.SearchSnippet-sub.go-textSubtle").
-		Find("strong").Text()
-	tmp = strings.TrimPrefix(tmp, "Other packages in module")
-	tmp = strings.Trim(tmp, ":")
-	moduleName = strings.TrimSpace(tmp)
+	moduleName := snippetModule(s)
 	if moduleName == "" {
 		return nil, nil
 	}
@@ -218,6 +266,106 @@ func extractOtherPackages(s *goquery.Selection) ([]string, error) {
 	return otherPackages, nil
 }
 
+// SymbolSearchResult 符号搜索的一个结果，例如 time 包中的 ParseDuration
+type SymbolSearchResult struct {
+	// Name 符号的名字，方法和字段带着类型，例如 Duration.String
+	Name string
+	// Kind function, method, type, constant, variable 或 field
+	Kind string
+	// Signature 符号的声明，例如 func ParseDuration(s string) (Duration, error)
+	Signature   string
+	Synopsis    string `json:",omitempty"`
+	PackagePath string
+	GoDocUrl    string
+	ImportedBy  int
+}
+
+// symbolKinds 旧版本的页面没有 SearchSnippet-symbolKind，按文本查找
+var symbolKinds = []string{"function", "method", "type", "constant", "variable", "field"}
+
+func extractSymbolSearchResult(ctx context.Context, html string, baseURL string, req SearchRequest) (*SearchResult, error) {
+	doc, err := getDoc(html)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []*SymbolSearchResult
+	doc.Find(".SearchSnippet").Each(func(i int, selection *goquery.Selection) {
+		symbol, _err := extractSymbolInfo(ctx, selection, baseURL)
+		if _err != nil {
+			err = multierr.Append(err, _err)
+			return
+		}
+		symbols = append(symbols, symbol)
+	})
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "extract symbol search result failed", "err", err)
+		return nil, err
+	}
+	if len(symbols) == 0 {
+		logging.FromContext(ctx).DebugContext(ctx, "no search snippet found in page")
+	}
+
+	result := &SearchResult{Symbols: symbols}
+	result.Total, result.NextPage = extractSearchPagination(doc, req, len(symbols))
+	return result, nil
+}
+
+func extractSymbolInfo(ctx context.Context, selection *goquery.Selection, baseURL string) (*SymbolSearchResult, error) {
+	title := findSnippetTitle(selection)
+	name := strings.TrimSpace(title.Contents().Not("span").Text())
+	href, _ := title.Attr("href")
+	href = strings.TrimSpace(href)
+
+	pkgPath := strings.Trim(strings.TrimSpace(title.Find(".SearchSnippet-header-path").Text()), "()")
+	if pkgPath == "" {
+		// 链接是 /import/path#Name，指定了版本时是 /module@version/sub#Name
+		pkgPath, _, _ = strings.Cut(strings.TrimPrefix(href, "/"), "#")
+		elems := strings.Split(pkgPath, "/")
+		for i := range elems {
+			elems[i], _ = splitVersion(elems[i])
+		}
+		pkgPath = strings.Join(elems, "/")
+	}
+
+	kind := strings.TrimSpace(selection.Find(".SearchSnippet-symbolKind").First().Text())
+	if kind == "" {
+		selection.Find(".SearchSnippet-infoLabel span").EachWithBreak(func(i int, s *goquery.Selection) bool {
+			text := strings.ToLower(strings.TrimSpace(s.Text()))
+			if slices.Contains(symbolKinds, text) {
+				kind = text
+				return false
+			}
+			return true
+		})
+	}
+
+	signature := strings.TrimSpace(findFirst(selection,
+		".SearchSnippet-symbolCode",
+		"[data-test-id='snippet-symbolSynopsis']",
+		"pre",
+	).First().Text())
+
+	synopsis, err := extractPackageSynopsis(selection)
+	if err != nil {
+		return nil, err
+	}
+	imptBy, err := extractImportedBy(ctx, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SymbolSearchResult{
+		Name:        name,
+		Kind:        kind,
+		Signature:   signature,
+		Synopsis:    synopsis,
+		PackagePath: pkgPath,
+		GoDocUrl:    baseURL + href,
+		ImportedBy:  imptBy,
+	}, nil
+}
+
 func getDoc(query string) (*goquery.Document, error) {
 	p, e := html.Parse(strings.NewReader(query))
 	if e != nil {
@@ -226,3 +374,25 @@ func getDoc(query string) (*goquery.Document, error) {
 
 	return goquery.NewDocumentFromNode(p), nil
 }
+
+// findSnippetTitle 搜索结果的标题，旧版本的 pkgsite 没有 data-test-id
+func findSnippetTitle(selection *goquery.Selection) *goquery.Selection {
+	return findFirst(selection,
+		"a[data-test-id='snippet-title']",
+		".SearchSnippet-headerContainer h2 a",
+		".SearchSnippet-header a",
+	).First()
+}
+
+// findFirst 按顺序尝试 selectors，返回第一个有结果的
+// 不同版本的 pkgsite 的 class 和结构略有不同，当前版本的 selector 放在前面
+func findFirst(s *goquery.Selection, selectors ...string) *goquery.Selection {
+	var found *goquery.Selection
+	for _, selector := range selectors {
+		found = s.Find(selector)
+		if found.Length() > 0 {
+			return found
+		}
+	}
+	return found
+}
@@ -0,0 +1,91 @@
+package godoc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractSymbolInfoPathFromHref(t *testing.T) {
+	tests := []struct {
+		href string
+		want string
+	}{
+		{"/github.com/example/mod/sub#Name", "github.com/example/mod/sub"},
+		{"/github.com/example/mod@v1.2.3/sub#Name", "github.com/example/mod/sub"},
+		{"/github.com/example/mod@v1.2.3#Name", "github.com/example/mod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.href, func(t *testing.T) {
+			doc, err := getDoc(`<div class="SearchSnippet"><div class="SearchSnippet-headerContainer"><h2><a href="` + tt.href + `">Name</a></h2></div></div>`)
+			require.NoError(t, err)
+			info, err := extractSymbolInfo(context.Background(), doc.Find(".SearchSnippet"), "https://pkg.go.dev")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, info.PackagePath)
+			assert.Equal(t, "https://pkg.go.dev"+tt.href, info.GoDocUrl)
+		})
+	}
+}
+
+func TestExtractSearchPagination(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		req      SearchRequest
+		n        int
+		total    int
+		nextPage int
+	}{
+		{
+			name:     "next link",
+			html:     `<div class="SearchResults-summary">1 – 25 of 78 results</div><a class="Pagination-next" href="/search?q=yaml&page=2">Next</a>`,
+			n:        25,
+			total:    78,
+			nextPage: 2,
+		},
+		{
+			name:  "last page",
+			html:  `<div class="SearchResults-summary">76 – 78 of 78 results</div><a class="Pagination-next" aria-disabled="true" href="/search?q=yaml&page=5">Next</a>`,
+			req:   SearchRequest{Page: 4},
+			n:     3,
+			total: 78,
+		},
+		{
+			name:     "about total without next link",
+			html:     `<div data-test-id="search-results-summary">1 – 10 of about 1,234 results</div>`,
+			req:      SearchRequest{Limit: 10},
+			n:        10,
+			total:    1234,
+			nextPage: 2,
+		},
+		{
+			name:  "last page by total without next link",
+			html:  `<div class="SearchResults-summary">51 – 60 of 60 results</div>`,
+			req:   SearchRequest{Page: 6, Limit: 10},
+			n:     10,
+			total: 60,
+		},
+		{
+			name: "no total without next link",
+			html: `<div class="SearchResults">results</div>`,
+			n:    25,
+		},
+		{
+			name:  "page past the end",
+			html:  `<div class="SearchResults-summary">of 78 results</div><a class="Pagination-next" href="/search?q=yaml&page=11">Next</a>`,
+			req:   SearchRequest{Page: 10},
+			total: 78,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := getDoc(tt.html)
+			require.NoError(t, err)
+			total, nextPage := extractSearchPagination(doc, tt.req, tt.n)
+			assert.Equal(t, tt.total, total)
+			assert.Equal(t, tt.nextPage, nextPage)
+		})
+	}
+}
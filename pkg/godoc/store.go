@@ -1,11 +1,18 @@
 package godoc
 
 import (
+	"context"
+	"encoding/binary"
 	"sync"
+	"time"
 
 	"github.com/dgraph-io/ristretto/v2"
+	"github.com/pkg/errors"
+	"github.com/yikakia/cachalot"
 	"github.com/yikakia/cachalot/core/cache"
+	"github.com/yikakia/cachalot/core/codec"
 	store_ristretto "github.com/yikakia/cachalot/stores/ristretto"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
 )
 
 var store = sync.OnceValue(func() cache.Store {
@@ -13,10 +20,11 @@ var store = sync.OnceValue(func() cache.Store {
 })
 
 func initStore() cache.Store {
+	cfg := getConfig().Cache
 	client, err := ristretto.NewCache(&ristretto.Config[string, any]{
-		NumCounters: 1 << 10,
-		MaxCost:     1 << 20,
-		BufferItems: 64,
+		NumCounters: cfg.NumCounters,
+		MaxCost:     cfg.MaxCost,
+		BufferItems: cfg.BufferItems,
 	})
 	if err != nil {
 		panic(err)
@@ -24,3 +32,163 @@ func initStore() cache.Store {
 	store := store_ristretto.New(client, store_ristretto.WithStoreName("basic-ristretto"))
 	return store
 }
+
+// pageCache 缓存抓取到的页面
+// TTL 之后仍然返回旧的页面，同时在后台刷新，刷新失败时保留旧的页面。MaxTTL 之后删除，下次请求同步回源
+// 同一个 key 同时只有一个回源请求，等待它的请求都取消之后才中断回源，
+// 所以一个请求取消或者超时不会让等待同一个页面的其他请求失败
+type pageCache struct {
+	cache  cache.Cache[[]byte]
+	loader func(ctx context.Context, key string) ([]byte, error)
+	ttl    time.Duration
+	maxTTL time.Duration
+	// timeout 回源的超时时间，0 表示不超时
+	timeout time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*pageCall
+}
+
+// pageCall 一个进行中的回源请求
+type pageCall struct {
+	done chan struct{}
+	page []byte
+	err  error
+	// waiters 等待结果的请求数，减到 0 时调用 cancel 中断回源
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newPageCache(name string, store cache.Store, cfg CacheConfig, timeout time.Duration, loader func(ctx context.Context, key string) ([]byte, error)) (*pageCache, error) {
+	b, err := cachalot.NewBuilder[[]byte](name, store)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	c, err := b.WithCompression(codec.GzipCompressionCodec{}).Build()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &pageCache{
+		cache:   c,
+		loader:  loader,
+		ttl:     cfg.TTL,
+		maxTTL:  cfg.MaxTTL,
+		timeout: timeout,
+		calls:   make(map[string]*pageCall),
+	}, nil
+}
+
+func (c *pageCache) get(ctx context.Context, key string) ([]byte, error) {
+	b, err := c.cache.Get(ctx, key)
+	if err == nil {
+		expireAt, page := decodePage(b)
+		if !expireAt.IsZero() && time.Now().After(expireAt) {
+			c.refresh(ctx, key)
+		}
+		reportCacheHit(ctx)
+		return page, nil
+	}
+	if !errors.Is(err, cache.ErrNotFound) {
+		return nil, errors.WithStack(err)
+	}
+	return c.load(ctx, key)
+}
+
+// load 等待回源的结果，ctx 结束时直接返回，最后一个等待的请求返回时中断回源
+func (c *pageCache) load(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	if !ok {
+		call = c.startLocked(ctx, key)
+	}
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		c.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+		}
+		c.mu.Unlock()
+		return nil, errors.WithStack(ctx.Err())
+	case <-call.done:
+		return call.page, call.err
+	}
+}
+
+// refresh 在后台回源，调用方已经拿到了旧的页面，所以不再上报进度
+// 后台回源没有调用方在等待，一直占着一个 waiter，不会被取消
+func (c *pageCache) refresh(ctx context.Context, key string) {
+	ctx = context.WithValue(ctx, progressKey{}, (*progressReporter)(nil))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.calls[key]; ok {
+		return
+	}
+	call := c.startLocked(ctx, key)
+	call.waiters++
+	go func() {
+		<-call.done
+		if call.err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "refresh page failed, keep the cached one", "key", key, "err", call.err)
+		}
+	}()
+}
+
+// startLocked 开始回源，调用时需要持有 c.mu
+// 回源的 ctx 保留 ctx 中的 logger 和进度回调，但是不随 ctx 取消，只在没有请求等待时取消
+func (c *pageCache) startLocked(ctx context.Context, key string) *pageCall {
+	fetchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &pageCall{done: make(chan struct{}), cancel: cancel}
+	c.calls[key] = call
+	go func() {
+		defer cancel()
+		call.page, call.err = c.fetch(fetchCtx, key)
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// fetch 回源并写入缓存，失败时不写入
+func (c *pageCache) fetch(ctx context.Context, key string) ([]byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	page, err := c.loader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cache.Set(ctx, key, encodePage(page, c.ttl), c.maxTTL); err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "write page to cache failed", "key", key, "err", err)
+	}
+	return page, nil
+}
+
+// encodePage 前 8 个字节是逻辑过期的 UnixNano，0 表示不过期
+func encodePage(page []byte, ttl time.Duration) []byte {
+	var expireAt int64
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl).UnixNano()
+	}
+	b := make([]byte, 8, 8+len(page))
+	binary.LittleEndian.PutUint64(b, uint64(expireAt))
+	return append(b, page...)
+}
+
+func decodePage(b []byte) (time.Time, []byte) {
+	if len(b) < 8 {
+		return time.Time{}, b
+	}
+	expireAt := int64(binary.LittleEndian.Uint64(b))
+	if expireAt == 0 {
+		return time.Time{}, b[8:]
+	}
+	return time.Unix(0, expireAt), b[8:]
+}
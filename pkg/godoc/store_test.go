@@ -0,0 +1,96 @@
+package godoc
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	store_ristretto "github.com/yikakia/cachalot/stores/ristretto"
+)
+
+func newTestPageCache(t *testing.T, loader func(ctx context.Context, key string) ([]byte, error)) *pageCache {
+	t.Helper()
+	client, err := ristretto.NewCache(&ristretto.Config[string, any]{NumCounters: 1000, MaxCost: 1 << 20, BufferItems: 64})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	c, err := newPageCache(t.Name(), store_ristretto.New(client), CacheConfig{}, time.Minute, loader)
+	require.NoError(t, err)
+	return c
+}
+
+func TestPageCacheCancelLastWaiter(t *testing.T) {
+	canceled := make(chan struct{})
+	c := newTestPageCache(t, func(ctx context.Context, key string) ([]byte, error) {
+		<-ctx.Done()
+		close(canceled)
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.load(ctx, "key")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("upstream request was not canceled after the last waiter left")
+	}
+}
+
+func TestPageCacheKeepFetchForOtherWaiters(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	c := newTestPageCache(t, func(ctx context.Context, key string) ([]byte, error) {
+		calls.Add(1)
+		select {
+		case <-release:
+			return []byte("page"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	type result struct {
+		page []byte
+		err  error
+	}
+	done := make(chan result)
+	go func() {
+		page, err := c.load(context.Background(), "key")
+		done <- result{page, err}
+	}()
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.load(ctx, "key")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	res := <-done
+	require.NoError(t, res.err)
+	assert.Equal(t, "page", string(res.page))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestPageCacheDoNotCacheErrors(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestPageCache(t, func(ctx context.Context, key string) ([]byte, error) {
+		if calls.Add(1) == 1 {
+			return nil, errors.New("upstream failed")
+		}
+		return []byte("page"), nil
+	})
+
+	_, err := c.get(context.Background(), "key")
+	require.Error(t, err)
+	page, err := c.get(context.Background(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "page", string(page))
+}
@@ -0,0 +1,130 @@
+package godoc
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/mod/semver"
+)
+
+// VersionList module 的版本，按主版本分组，从新到旧排列
+type VersionList struct {
+	// Latest 最新的版本，跳过撤回的版本，有正式版本时不使用预发布版本
+	Latest string
+	Majors []*MajorVersion
+}
+
+// MajorVersion 同一个主版本的版本，例如 v2
+type MajorVersion struct {
+	Major string
+	// Latest 这个主版本中最新的版本，规则和 VersionList.Latest 一样
+	Latest   string
+	Versions []*VersionInfo
+}
+
+type VersionInfo struct {
+	Version string
+	// Published 发布的时间，未知时为零值
+	Published time.Time `json:",omitzero"`
+	Retracted bool      `json:",omitempty"`
+	// RetractRationale 撤回的原因，只有本地的 go.mod 中有
+	RetractRationale string `json:",omitempty"`
+	Prerelease       bool   `json:",omitempty"`
+	// Latest 是否是 VersionList.Latest
+	Latest bool `json:",omitempty"`
+}
+
+// newVersionList 排序并按主版本分组，同时标记预发布和最新的版本
+func newVersionList(versions []*VersionInfo) *VersionList {
+	versions = slices.DeleteFunc(versions, func(v *VersionInfo) bool {
+		return !semver.IsValid(v.Version)
+	})
+	slices.SortStableFunc(versions, func(a, b *VersionInfo) int {
+		return semver.Compare(b.Version, a.Version)
+	})
+
+	result := &VersionList{}
+	var major *MajorVersion
+	for _, v := range versions {
+		v.Prerelease = semver.Prerelease(v.Version) != ""
+		if major == nil || major.Major != semver.Major(v.Version) {
+			major = &MajorVersion{Major: semver.Major(v.Version)}
+			result.Majors = append(result.Majors, major)
+		}
+		major.Versions = append(major.Versions, v)
+	}
+
+	for _, m := range result.Majors {
+		latest := latestVersionInfo(m.Versions)
+		if latest == nil {
+			continue
+		}
+		m.Latest = latest.Version
+		// 主版本从新到旧排列，第一个有最新版本的就是整体最新的
+		if result.Latest == "" {
+			result.Latest = latest.Version
+			latest.Latest = true
+		}
+	}
+	return result
+}
+
+// latestVersionInfo versions 需要从新到旧排列
+func latestVersionInfo(versions []*VersionInfo) *VersionInfo {
+	var latestPre *VersionInfo
+	for _, v := range versions {
+		if v.Retracted {
+			continue
+		}
+		if !v.Prerelease {
+			return v
+		}
+		if latestPre == nil {
+			latestPre = v
+		}
+	}
+	return latestPre
+}
+
+func extractVersionList(html string) (*VersionList, error) {
+	doc, err := getDoc(html)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*VersionInfo
+	// 只取当前 module 的版本，页面后面还有包含这个包的其他 module 的版本
+	doc.Find("div.Versions-list").First().
+		Find("div.Version-tag").
+		Each(func(i int, s *goquery.Selection) {
+			v := strings.TrimSpace(s.Find("a").First().Text())
+			if v == "" {
+				return
+			}
+			info := &VersionInfo{Version: v}
+			// 发布时间和撤回的标记在紧跟着的 Version-commitTime 里
+			if commit := s.NextFiltered("div.Version-commitTime"); commit.Length() > 0 {
+				info.Published = parseCommitTime(commit.Contents().FilterFunction(func(i int, s *goquery.Selection) bool {
+					return goquery.NodeName(s) == "#text"
+				}).Text())
+				info.Retracted = commit.Find("[data-test-id='Version-retracted']").Length() > 0 ||
+					strings.Contains(strings.ToLower(commit.Text()), "retracted")
+			}
+			versions = append(versions, info)
+		})
+
+	return newVersionList(versions), nil
+}
+
+// parseCommitTime 解析失败时返回零值
+func parseCommitTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"Jan _2, 2006", "Jan 2, 2006", time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
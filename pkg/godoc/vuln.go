@@ -0,0 +1,365 @@
+package godoc
+
+import (
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+	"golang.org/x/mod/semver"
+)
+
+// stdlibModule 漏洞数据库中标准库的 module path
+const stdlibModule = "stdlib"
+
+// Vuln 一个已知的漏洞
+type Vuln struct {
+	// ID Go 漏洞数据库的 ID，例如 GO-2023-1571
+	ID string
+	// Aliases CVE 和 GHSA 的 ID
+	Aliases []string `json:",omitempty"`
+	Summary string   `json:",omitempty"`
+	// Packages 受影响的包，为空时整个 module 都受影响
+	Packages []*VulnPackage `json:",omitempty"`
+	// Fixed 修复了漏洞的版本，为空表示还没有修复
+	Fixed string `json:",omitempty"`
+	URL   string `json:",omitempty"`
+}
+
+type VulnPackage struct {
+	Path string
+	// Symbols 受影响的函数和方法，例如 Server.Serve，为空时整个包都受影响
+	Symbols []string `json:",omitempty"`
+}
+
+type VulnRequest struct {
+	// PackageName 包或者 module 的 import path，可以带版本
+	PackageName string
+	// Version 优先于 PackageName 中的版本，为空时列出所有版本的漏洞
+	Version string
+}
+
+// VulnReport 影响 ImportPath 的 Version 版本的漏洞
+type VulnReport struct {
+	ImportPath string
+	// Module 漏洞数据库中 ImportPath 所在的 module，没有任何漏洞时为空
+	Module  string `json:",omitempty"`
+	Version string `json:",omitempty"`
+	Vulns   []*Vuln
+}
+
+// GetVulnerabilities 从 Config.VulnDB 中查询漏洞，不会请求网络
+func GetVulnerabilities(ctx context.Context, req VulnRequest) (*VulnReport, error) {
+	db := vulnDB()
+	if db == nil {
+		return nil, errors.New("vulnerability database is not configured, set godoc.vulnDB to a copy of https://vuln.go.dev")
+	}
+	importPath, version := GetPackageRequest{PackageName: req.PackageName, Version: req.Version}.pathAndVersion()
+	return db.query(ctx, importPath, version)
+}
+
+// attachVulns 配置了漏洞数据库时在文档中加上当前版本的漏洞，失败时只记录日志
+// 不修改 doc，它可能在缓存中
+func attachVulns(ctx context.Context, importPath string, doc *PackageDocument) *PackageDocument {
+	db := vulnDB()
+	if db == nil || doc.Version == "" {
+		return doc
+	}
+	report, err := db.query(ctx, importPath, doc.Version)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "query vulnerabilities failed", "package", importPath, "err", err)
+		return doc
+	}
+	if len(report.Vulns) == 0 {
+		return doc
+	}
+	result := *doc
+	result.Vulns = report.Vulns
+	return &result
+}
+
+var vulnDB = sync.OnceValue(func() *vulnDatabase {
+	dir := getConfig().VulnDB
+	if dir == "" {
+		return nil
+	}
+	return newVulnDatabase(dir)
+})
+
+// vulnDatabase 读取 vuln.go.dev 格式的目录，例如解压 https://vuln.go.dev/vulndb.zip 得到的目录
+//
+//	index/modules.json  有漏洞的 module 和它们的漏洞 ID
+//	ID/GO-2023-1571.json  OSV 格式的漏洞
+//
+// 文件也可以是 gzip 压缩的 .json.gz
+type vulnDatabase struct {
+	dir string
+
+	mu sync.Mutex
+	// modules 读取成功后才缓存，失败时下次查询重新读取
+	modules []vulnModule
+}
+
+type vulnModule struct {
+	Path  string `json:"path"`
+	Vulns []struct {
+		ID string `json:"id"`
+	} `json:"vulns"`
+}
+
+func newVulnDatabase(dir string) *vulnDatabase {
+	return &vulnDatabase{dir: dir}
+}
+
+func (db *vulnDatabase) loadModules() ([]vulnModule, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.modules != nil {
+		return db.modules, nil
+	}
+	modules := []vulnModule{}
+	if err := db.readJSON(filepath.Join("index", "modules.json"), &modules); err != nil {
+		return nil, err
+	}
+	db.modules = modules
+	return modules, nil
+}
+
+// readJSON 不存在 name 时读取 name.gz
+func (db *vulnDatabase) readJSON(name string, v any) error {
+	path := filepath.Join(db.dir, name)
+	var r io.Reader
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.Open(path + ".gz")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer f.Close()
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return errors.Wrapf(err, "read %s.gz", path)
+		}
+		r = gr
+	} else if err != nil {
+		return errors.WithStack(err)
+	} else {
+		defer f.Close()
+		r = f
+	}
+	return errors.Wrapf(json.NewDecoder(r).Decode(v), "decode %s", path)
+}
+
+// findModule 返回包含 importPath 的 module，有嵌套的 module 时使用最长的
+func (db *vulnDatabase) findModule(importPath string) (*vulnModule, error) {
+	modules, err := db.loadModules()
+	if err != nil {
+		return nil, err
+	}
+	var found *vulnModule
+	for i, m := range modules {
+		var ok bool
+		if m.Path == stdlibModule {
+			ok = isStdImportPath(importPath)
+		} else {
+			ok = importPath == m.Path || strings.HasPrefix(importPath, m.Path+"/")
+		}
+		if ok && (found == nil || len(m.Path) > len(found.Path)) {
+			found = &modules[i]
+		}
+	}
+	return found, nil
+}
+
+func (db *vulnDatabase) query(ctx context.Context, importPath, version string) (*VulnReport, error) {
+	report := &VulnReport{ImportPath: importPath, Version: version, Vulns: []*Vuln{}}
+	m, err := db.findModule(importPath)
+	if err != nil || m == nil {
+		return report, err
+	}
+	report.Module = m.Path
+
+	var v string
+	if version != "" {
+		v = toSemver(version, m.Path)
+		if !semver.IsValid(v) {
+			return nil, errors.Errorf("invalid version %q", version)
+		}
+	}
+	for _, ref := range m.Vulns {
+		if ctx.Err() != nil {
+			return nil, errors.WithStack(ctx.Err())
+		}
+		var entry osvEntry
+		if err := db.readJSON(filepath.Join("ID", ref.ID+".json"), &entry); err != nil {
+			return nil, err
+		}
+		if vuln := entry.toVuln(m.Path, importPath, v); vuln != nil {
+			report.Vulns = append(report.Vulns, vuln)
+		}
+	}
+	return report, nil
+}
+
+// osvEntry OSV 格式的漏洞，只保留用到的字段，见 https://go.dev/security/vuln/database#schema
+type osvEntry struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Summary  string   `json:"summary"`
+	Details  string   `json:"details"`
+	Affected []struct {
+		Package struct {
+			Name string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string     `json:"type"`
+			Events []osvEvent `json:"events"`
+		} `json:"ranges"`
+		EcosystemSpecific struct {
+			Imports []struct {
+				Path    string   `json:"path"`
+				Symbols []string `json:"symbols"`
+			} `json:"imports"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		URL string `json:"url"`
+	} `json:"database_specific"`
+}
+
+// osvEvent 版本没有 v 前缀，introduced 为 0 表示从第一个版本开始
+type osvEvent struct {
+	Introduced string `json:"introduced"`
+	Fixed      string `json:"fixed"`
+}
+
+// toVuln version 为空时不检查版本，importPath 是 module path 时包括 module 中所有的包
+// 不影响 importPath 的 version 时返回 nil
+func (e *osvEntry) toVuln(modulePath, importPath, version string) *Vuln {
+	vuln := &Vuln{
+		ID:      e.ID,
+		Aliases: e.Aliases,
+		Summary: cmp.Or(e.Summary, firstLine(e.Details)),
+		URL:     e.DatabaseSpecific.URL,
+	}
+	affected := false
+	for _, a := range e.Affected {
+		if a.Package.Name != modulePath {
+			continue
+		}
+		var pkgs []*VulnPackage
+		for _, imp := range a.EcosystemSpecific.Imports {
+			if imp.Path == importPath || importPath == modulePath {
+				pkgs = append(pkgs, &VulnPackage{Path: imp.Path, Symbols: imp.Symbols})
+			}
+		}
+		// 列出了受影响的包，但是没有 importPath
+		if len(a.EcosystemSpecific.Imports) > 0 && len(pkgs) == 0 {
+			continue
+		}
+
+		for _, r := range a.Ranges {
+			if r.Type != "SEMVER" {
+				continue
+			}
+			fixed, ok := fixedVersion(r.Events, version)
+			if !ok {
+				continue
+			}
+			affected = true
+			vuln.Packages = append(vuln.Packages, pkgs...)
+			if fixed != "" && (vuln.Fixed == "" || semver.Compare(fixed, vuln.Fixed) > 0) {
+				vuln.Fixed = fixed
+			}
+			break
+		}
+	}
+	if !affected {
+		return nil
+	}
+	if vuln.Fixed != "" {
+		vuln.Fixed = fromSemver(vuln.Fixed, modulePath)
+	}
+	return vuln
+}
+
+// fixedVersion 判断 version 是否在 events 描述的范围中，返回修复它的版本
+// version 为空时总是受影响，返回最后一个修复的版本
+func fixedVersion(events []osvEvent, version string) (string, bool) {
+	if version == "" {
+		var fixed string
+		for _, e := range events {
+			if e.Fixed != "" {
+				fixed = "v" + e.Fixed
+			}
+		}
+		return fixed, len(events) > 0
+	}
+
+	// events 按版本排列，依次进入和离开受影响的范围
+	affected := false
+	var fixed string
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || semver.Compare(version, "v"+e.Introduced) >= 0 {
+				affected = true
+				fixed = ""
+			}
+		case e.Fixed != "":
+			if semver.Compare(version, "v"+e.Fixed) >= 0 {
+				affected = false
+			} else if affected && fixed == "" {
+				fixed = "v" + e.Fixed
+			}
+		}
+	}
+	return fixed, affected
+}
+
+// toSemver 把标准库的版本转成漏洞数据库中的格式
+// go1.21.3 转成 v1.21.3，go1.21 转成 v1.21.0，go1.21rc2 转成 v1.21.0-rc.2
+func toSemver(version, modulePath string) string {
+	if modulePath != stdlibModule || strings.HasPrefix(version, "v") {
+		return version
+	}
+	v := strings.TrimPrefix(version, "go")
+	var pre string
+	for _, tag := range []string{"rc", "beta"} {
+		if base, n, ok := strings.Cut(v, tag); ok {
+			v, pre = base, "-"+tag+"."+n
+			break
+		}
+	}
+	if strings.Count(v, ".") == 1 {
+		v += ".0"
+	}
+	return "v" + v + pre
+}
+
+// fromSemver toSemver 的逆操作，和 x/vuln 一样，预发布版本和 go1.21 之前的版本去掉 .0
+// v1.21.0-rc.2 转成 go1.21rc2，v1.20.0 转成 go1.20，v1.21.0 转成 go1.21.0
+func fromSemver(version, modulePath string) string {
+	if modulePath != stdlibModule {
+		return version
+	}
+	v, pre, _ := strings.Cut(strings.TrimPrefix(version, "v"), "-")
+	if pre != "" || semver.Compare("v"+v, "v1.21.0") < 0 {
+		if strings.Count(v, ".") == 2 {
+			v = strings.TrimSuffix(v, ".0")
+		}
+	}
+	return "go" + v + strings.ReplaceAll(pre, ".", "")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
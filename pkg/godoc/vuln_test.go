@@ -0,0 +1,143 @@
+package godoc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedVersion(t *testing.T) {
+	// 两个受影响的范围：[0, 1.2.0) 和 [1.3.0, 1.3.5)
+	events := []osvEvent{
+		{Introduced: "0"},
+		{Fixed: "1.2.0"},
+		{Introduced: "1.3.0"},
+		{Fixed: "1.3.5"},
+	}
+	tests := []struct {
+		version  string
+		fixed    string
+		affected bool
+	}{
+		{"v1.1.0", "v1.2.0", true},
+		{"v1.2.0", "", false},
+		{"v1.2.5", "", false},
+		{"v1.3.0", "v1.3.5", true},
+		{"v1.3.4", "v1.3.5", true},
+		{"v1.4.0", "", false},
+		{"", "v1.3.5", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			fixed, affected := fixedVersion(events, tt.version)
+			assert.Equal(t, tt.fixed, fixed)
+			assert.Equal(t, tt.affected, affected)
+		})
+	}
+
+	t.Run("not fixed", func(t *testing.T) {
+		fixed, affected := fixedVersion([]osvEvent{{Introduced: "1.0.0"}}, "v2.0.0")
+		assert.Equal(t, "", fixed)
+		assert.True(t, affected)
+	})
+}
+
+func TestStdlibVersion(t *testing.T) {
+	tests := []struct {
+		goVersion string
+		semver    string
+	}{
+		{"go1.21.3", "v1.21.3"},
+		{"go1.21.0", "v1.21.0"},
+		{"go1.20", "v1.20.0"},
+		{"go1.21rc2", "v1.21.0-rc.2"},
+		{"go1.22beta1", "v1.22.0-beta.1"},
+		{"go1.20.1rc1", "v1.20.1-rc.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goVersion, func(t *testing.T) {
+			assert.Equal(t, tt.semver, toSemver(tt.goVersion, stdlibModule))
+			assert.Equal(t, tt.goVersion, fromSemver(tt.semver, stdlibModule))
+		})
+	}
+
+	assert.Equal(t, "v1.2.3", toSemver("v1.2.3", "example.com/mod"))
+	assert.Equal(t, "v1.2.3", fromSemver("v1.2.3", "example.com/mod"))
+}
+
+const testOSVEntry = `{
+  "id": "GO-2023-2102",
+  "aliases": ["CVE-2023-39325", "GHSA-4374-p667-p6c8"],
+  "summary": "HTTP/2 rapid reset can cause excessive work in net/http",
+  "affected": [
+    {
+      "package": {"name": "stdlib"},
+      "ranges": [{"type": "SEMVER", "events": [
+        {"introduced": "0"}, {"fixed": "1.20.10"},
+        {"introduced": "1.21.0-0"}, {"fixed": "1.21.3"}
+      ]}],
+      "ecosystem_specific": {"imports": [{"path": "net/http", "symbols": ["Server.Serve"]}]}
+    },
+    {
+      "package": {"name": "golang.org/x/net"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.17.0"}]}],
+      "ecosystem_specific": {"imports": [{"path": "golang.org/x/net/http2", "symbols": ["Server.ServeConn"]}]}
+    }
+  ],
+  "database_specific": {"url": "https://pkg.go.dev/vuln/GO-2023-2102"}
+}`
+
+func TestToVuln(t *testing.T) {
+	var entry osvEntry
+	require.NoError(t, json.Unmarshal([]byte(testOSVEntry), &entry))
+
+	t.Run("stdlib affected", func(t *testing.T) {
+		vuln := entry.toVuln(stdlibModule, "net/http", toSemver("go1.21rc2", stdlibModule))
+		require.NotNil(t, vuln)
+		assert.Equal(t, "GO-2023-2102", vuln.ID)
+		assert.Equal(t, "go1.21.3", vuln.Fixed)
+		assert.Equal(t, []*VulnPackage{{Path: "net/http", Symbols: []string{"Server.Serve"}}}, vuln.Packages)
+		assert.Equal(t, "https://pkg.go.dev/vuln/GO-2023-2102", vuln.URL)
+	})
+	t.Run("stdlib older branch", func(t *testing.T) {
+		vuln := entry.toVuln(stdlibModule, "net/http", toSemver("go1.20.9", stdlibModule))
+		require.NotNil(t, vuln)
+		assert.Equal(t, "go1.20.10", vuln.Fixed)
+	})
+	t.Run("stdlib fixed", func(t *testing.T) {
+		assert.Nil(t, entry.toVuln(stdlibModule, "net/http", toSemver("go1.21.3", stdlibModule)))
+	})
+	t.Run("other package", func(t *testing.T) {
+		assert.Nil(t, entry.toVuln(stdlibModule, "net/url", ""))
+	})
+	t.Run("module path", func(t *testing.T) {
+		vuln := entry.toVuln("golang.org/x/net", "golang.org/x/net", "v0.16.0")
+		require.NotNil(t, vuln)
+		assert.Equal(t, "v0.17.0", vuln.Fixed)
+		assert.Equal(t, []*VulnPackage{{Path: "golang.org/x/net/http2", Symbols: []string{"Server.ServeConn"}}}, vuln.Packages)
+	})
+}
+
+func TestVulnDatabaseRetryIndex(t *testing.T) {
+	dir := t.TempDir()
+	db := newVulnDatabase(dir)
+	_, err := db.query(context.Background(), "net/http", "go1.21.0")
+	require.Error(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "index"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ID"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index", "modules.json"),
+		[]byte(`[{"path":"stdlib","vulns":[{"id":"GO-2023-2102"}]}]`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ID", "GO-2023-2102.json"), []byte(testOSVEntry), 0o644))
+
+	report, err := db.query(context.Background(), "net/http", "go1.21.0")
+	require.NoError(t, err)
+	assert.Equal(t, stdlibModule, report.Module)
+	require.Len(t, report.Vulns, 1)
+	assert.Equal(t, "go1.21.3", report.Vulns[0].Fixed)
+}
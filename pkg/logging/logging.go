@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/multierr"
+)
+
+// LoggerName 发给客户端的日志中的 logger 字段
+const LoggerName = "godoc-mcp-server"
+
+type loggerKey struct{}
+
+// WithLogger 把 logger 放到 ctx 里，之后通过 FromContext 获取
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext 返回 ctx 中的 logger，没有的话返回 slog.Default()
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// WithSession 返回的 ctx 中的 logger 除了写到默认的 logger 以外，还会通过 notifications/message
+// 发给 session 对应的客户端。发给客户端的级别由客户端通过 logging/setLevel 设置，没有设置时不发送
+func WithSession(ctx context.Context, ss *mcp.ServerSession) context.Context {
+	if ss == nil {
+		return ctx
+	}
+	h := tee{
+		slog.Default().Handler(),
+		mcp.NewLoggingHandler(ss, &mcp.LoggingHandlerOptions{LoggerName: LoggerName}),
+	}
+	return WithLogger(ctx, slog.New(h))
+}
+
+// tee 把日志同时写到多个 handler
+type tee []slog.Handler
+
+func (t tee) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t tee) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	for _, h := range t {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		err = multierr.Append(err, h.Handle(ctx, r.Clone()))
+	}
+	return err
+}
+
+func (t tee) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hs := make(tee, 0, len(t))
+	for _, h := range t {
+		hs = append(hs, h.WithAttrs(attrs))
+	}
+	return hs
+}
+
+func (t tee) WithGroup(name string) slog.Handler {
+	hs := make(tee, 0, len(t))
+	for _, h := range t {
+		hs = append(hs, h.WithGroup(name))
+	}
+	return hs
+}
@@ -0,0 +1,55 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+)
+
+// ChooseLibrary 搜索候选的包，让 llm 根据需求挑选
+func ChooseLibrary() (*mcp.Prompt, mcp.PromptHandler) {
+	p := &mcp.Prompt{
+		Name:        "choose-a-library-for",
+		Title:       "Choose a Go library",
+		Description: "search pkg.go.dev for golang packages matching a requirement and compare the candidates",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "requirement", Description: "what the library should do, e.g. parse yaml with comments preserved", Required: true},
+			{Name: "query", Description: "query for searching pkg.go.dev, empty means use the requirement"},
+		},
+	}
+
+	return p, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ctx = logging.WithSession(ctx, req.Session)
+		requirement, err := requiredArg(req, "requirement")
+		if err != nil {
+			return nil, err
+		}
+		query := req.Params.Arguments["query"]
+		if query == "" {
+			query = requirement
+		}
+
+		result, err := godoc.Search(ctx, godoc.SearchRequest{Query: query})
+		if err != nil {
+			return nil, errors.WithMessage(err, "search failed")
+		}
+
+		candidates := fmt.Sprintf("Search results of %q on pkg.go.dev:\n\n%s", query, result.Markdown())
+		instruction := fmt.Sprintf("I need a Go library for: %s\n\n"+
+			"Compare the candidates above. Prefer packages from the standard library, then widely imported and "+
+			"maintained ones, and point out forks or toy projects. Recommend one, explain why, and use the "+
+			"getPackageInfo tool on it before showing a usage example.", requirement)
+
+		return &mcp.GetPromptResult{
+			Description: "choose a library for " + requirement,
+			Messages: []*mcp.PromptMessage{
+				userText(candidates),
+				userText(instruction),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,55 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+	"github.com/yikakia/godoc-mcp-server/pkg/resource"
+)
+
+// ExplainPackage 让 llm 根据文档讲解一个包的用法
+func ExplainPackage() (*mcp.Prompt, mcp.PromptHandler) {
+	p := &mcp.Prompt{
+		Name:        "explain-package",
+		Title:       "Explain a Go package",
+		Description: "explain what a golang package is for and how to use it, based on its documentation from pkg.go.dev",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "importPath", Description: "full import path of the package, e.g. github.com/yikakia/cachalot", Required: true},
+			{Name: "version", Description: "version of the package, empty means the latest"},
+			{Name: "focus", Description: "what the user cares about most, e.g. a type, a function or a use case"},
+		},
+	}
+
+	return p, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ctx = logging.WithSession(ctx, req.Session)
+		importPath, err := requiredArg(req, "importPath")
+		if err != nil {
+			return nil, err
+		}
+		version := req.Params.Arguments["version"]
+		focus := req.Params.Arguments["focus"]
+
+		doc, err := resource.ReadPkgDoc(ctx, importPath, version)
+		if err != nil {
+			return nil, err
+		}
+
+		instruction := fmt.Sprintf("Explain the Go package %s using the attached documentation. "+
+			"Start with what problem it solves, then walk through the most important types and functions, "+
+			"and finish with a short, idiomatic usage example with the correct import statement. "+
+			"Only use APIs that appear in the documentation.", importPath)
+		if focus != "" {
+			instruction += fmt.Sprintf(" Focus on: %s.", focus)
+		}
+
+		return &mcp.GetPromptResult{
+			Description: "explain " + importPath,
+			Messages: []*mcp.PromptMessage{
+				userResource(doc),
+				userText(instruction),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,64 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+	"github.com/yikakia/godoc-mcp-server/pkg/resource"
+)
+
+// MigrateVersions 把两个版本的文档都给 llm，让它总结迁移的步骤
+func MigrateVersions() (*mcp.Prompt, mcp.PromptHandler) {
+	p := &mcp.Prompt{
+		Name:        "migrate-between-versions",
+		Title:       "Migrate between versions of a Go package",
+		Description: "compare the documentation of two versions of a golang package and write a migration guide",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "importPath", Description: "full import path of the package, e.g. github.com/yikakia/cachalot", Required: true},
+			{Name: "from", Description: "the version currently used, e.g. v1.2.0", Required: true},
+			{Name: "to", Description: "the version to migrate to, e.g. v2.0.0", Required: true},
+		},
+	}
+
+	return p, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ctx = logging.WithSession(ctx, req.Session)
+		importPath, err := requiredArg(req, "importPath")
+		if err != nil {
+			return nil, err
+		}
+		from, err := requiredArg(req, "from")
+		if err != nil {
+			return nil, err
+		}
+		to, err := requiredArg(req, "to")
+		if err != nil {
+			return nil, err
+		}
+
+		fromDoc, err := resource.ReadPkgDoc(ctx, importPath, from)
+		if err != nil {
+			return nil, err
+		}
+		toDoc, err := resource.ReadPkgDoc(ctx, importPath, to)
+		if err != nil {
+			return nil, err
+		}
+
+		instruction := fmt.Sprintf("The attached documents are the documentation of the Go package %s at %s and at %s. "+
+			"Write a migration guide from %s to %s: list removed, renamed and changed APIs with their replacements, "+
+			"new APIs worth adopting, and behaviour changes mentioned in the comments. "+
+			"Show before/after code for each breaking change. Note that a new major version usually changes the import path.",
+			importPath, from, to, from, to)
+
+		return &mcp.GetPromptResult{
+			Description: fmt.Sprintf("migrate %s from %s to %s", importPath, from, to),
+			Messages: []*mcp.PromptMessage{
+				userResource(fromDoc),
+				userResource(toDoc),
+				userText(instruction),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,28 @@
+package prompt
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
+)
+
+func requiredArg(req *mcp.GetPromptRequest, name string) (string, error) {
+	v := req.Params.Arguments[name]
+	if v == "" {
+		return "", errors.Errorf("argument %s is required", name)
+	}
+	return v, nil
+}
+
+func userText(text string) *mcp.PromptMessage {
+	return &mcp.PromptMessage{
+		Role:    "user",
+		Content: &mcp.TextContent{Text: text},
+	}
+}
+
+func userResource(contents *mcp.ResourceContents) *mcp.PromptMessage {
+	return &mcp.PromptMessage{
+		Role:    "user",
+		Content: &mcp.EmbeddedResource{Resource: contents},
+	}
+}
@@ -0,0 +1,82 @@
+package resource
+
+import (
+	"context"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+)
+
+const (
+	PkgDocScheme = "godoc://"
+	// PkgDocURITemplate 包的最新文档，例如 godoc://github.com/yikakia/cachalot
+	PkgDocURITemplate = PkgDocScheme + "{+importPath}"
+	// PkgDocVersionURITemplate 包的指定版本的文档，例如 godoc://github.com/yikakia/cachalot@v0.1.0
+	PkgDocVersionURITemplate = PkgDocScheme + "{+importPath}@{version}"
+)
+
+// GetPkgDocResource 返回包文档的 resource，文档会被渲染成 markdown
+// 和 getPackageInfo 共用 godoc.GetPackageDocument 的缓存
+func GetPkgDocResource() mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		ctx = logging.WithSession(ctx, req.Session)
+		importPath, version, err := ParsePkgDocURI(req.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := ReadPkgDoc(ctx, importPath, version)
+		if err != nil {
+			return nil, err
+		}
+		contents.URI = req.Params.URI
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{contents},
+		}, nil
+	}
+}
+
+// ReadPkgDoc 获取包的文档并渲染成 markdown，version 为空时获取最新版本
+func ReadPkgDoc(ctx context.Context, importPath, version string) (*mcp.ResourceContents, error) {
+	pkgDoc, err := godoc.GetPackageDocument(ctx, godoc.GetPackageRequest{
+		PackageName: importPath,
+		Version:     version,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "get pkg doc failed")
+	}
+
+	return &mcp.ResourceContents{
+		URI:      PkgDocURI(importPath, version),
+		MIMEType: "text/markdown",
+		Text:     pkgDoc.Markdown(importPath),
+	}, nil
+}
+
+// PkgDocURI 拼接包文档的 uri，是 ParsePkgDocURI 的逆过程
+func PkgDocURI(importPath, version string) string {
+	if version == "" {
+		return PkgDocScheme + importPath
+	}
+	return PkgDocScheme + importPath + "@" + version
+}
+
+// ParsePkgDocURI 把 godoc://importPath[@version] 拆成 import path 和版本
+func ParsePkgDocURI(uri string) (importPath, version string, err error) {
+	if !strings.HasPrefix(uri, PkgDocScheme) {
+		return "", "", mcp.ResourceNotFoundError(uri)
+	}
+	importPath = strings.Trim(strings.TrimPrefix(uri, PkgDocScheme), "/")
+	// 版本号里不会有 /，有的话说明 @ 是路径的一部分
+	if i := strings.LastIndex(importPath, "@"); i >= 0 && !strings.Contains(importPath[i:], "/") {
+		importPath, version = importPath[:i], importPath[i+1:]
+	}
+	if importPath == "" {
+		return "", "", mcp.ResourceNotFoundError(uri)
+	}
+	return importPath, version, nil
+}
@@ -0,0 +1,57 @@
+package tool
+
+import (
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
+)
+
+// Description 是工具以及各个参数的描述，会原样提供给 llm
+type Description struct {
+	Description string `yaml:"description"`
+	// Params 参数名(json 名)到参数描述的映射，为空的参数使用 jsonschema tag 里的描述
+	Params map[string]string `yaml:"params"`
+}
+
+// Merge 用 other 中不为空的部分覆盖 d
+func (d Description) Merge(other Description) Description {
+	if other.Description != "" {
+		d.Description = other.Description
+	}
+	if len(other.Params) == 0 {
+		return d
+	}
+	params := make(map[string]string, len(d.Params)+len(other.Params))
+	for k, v := range d.Params {
+		params[k] = v
+	}
+	for k, v := range other.Params {
+		if v != "" {
+			params[k] = v
+		}
+	}
+	d.Params = params
+	return d
+}
+
+// NewTool 根据描述构造 mcp.Tool
+// 参数的 schema 由 In 推导，然后用 desc.Params 覆盖各个参数的描述
+func NewTool[In any](name string, desc Description) (*mcp.Tool, error) {
+	schema, err := jsonschema.For[In](nil)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "infer input schema of %s failed", name)
+	}
+	for param, d := range desc.Params {
+		prop, ok := schema.Properties[param]
+		if !ok {
+			return nil, errors.Errorf("tool %s has no param %s", name, param)
+		}
+		prop.Description = d
+	}
+
+	return &mcp.Tool{
+		Name:        name,
+		Description: desc.Description,
+		InputSchema: schema,
+	}, nil
+}
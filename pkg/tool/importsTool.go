@@ -0,0 +1,52 @@
+package tool
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+)
+
+type ImportsParams struct {
+	PkgName string `json:"pkgName" jsonschema:"the import path of the package, can be followed by @version"`
+	Page    int    `json:"page,omitempty" jsonschema:"page number starting from 1, use NextPage of the last result"`
+	Limit   int    `json:"limit,omitempty" jsonschema:"max number of packages in one page, default 100"`
+}
+
+func (p ImportsParams) request() godoc.ImportsRequest {
+	return godoc.ImportsRequest{
+		PackageName: p.PkgName,
+		Page:        p.Page,
+		Limit:       p.Limit,
+	}
+}
+
+func GetImportsTool() mcp.ToolHandlerFor[ImportsParams, *godoc.ImportList] {
+	return func(ctx context.Context, c *mcp.CallToolRequest, input ImportsParams) (*mcp.CallToolResult, *godoc.ImportList, error) {
+		ctx = logging.WithSession(ctx, c.Session)
+		ctx = withProgress(ctx, c)
+		imports, err := godoc.GetImports(ctx, input.request())
+		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "get imports failed", "package", input.PkgName, "err", err)
+			return nil, nil, errors.WithMessage(err, "get imports failed")
+		}
+
+		return nil, imports, nil
+	}
+}
+
+func GetImportedByTool() mcp.ToolHandlerFor[ImportsParams, *godoc.ImportList] {
+	return func(ctx context.Context, c *mcp.CallToolRequest, input ImportsParams) (*mcp.CallToolResult, *godoc.ImportList, error) {
+		ctx = logging.WithSession(ctx, c.Session)
+		ctx = withProgress(ctx, c)
+		importedBy, err := godoc.GetImportedBy(ctx, input.request())
+		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "get imported by failed", "package", input.PkgName, "err", err)
+			return nil, nil, errors.WithMessage(err, "get imported by failed")
+		}
+
+		return nil, importedBy, nil
+	}
+}
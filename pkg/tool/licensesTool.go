@@ -0,0 +1,28 @@
+package tool
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+)
+
+type GetLicensesParams struct {
+	PkgName string `json:"pkgName" jsonschema:"the import path of the package, can be followed by @version"`
+}
+
+func GetLicensesTool() mcp.ToolHandlerFor[GetLicensesParams, *godoc.LicenseList] {
+	return func(ctx context.Context, c *mcp.CallToolRequest, input GetLicensesParams) (*mcp.CallToolResult, *godoc.LicenseList, error) {
+		ctx = logging.WithSession(ctx, c.Session)
+		ctx = withProgress(ctx, c)
+		licenses, err := godoc.GetLicenses(ctx, input.PkgName)
+		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "get licenses failed", "package", input.PkgName, "err", err)
+			return nil, nil, errors.WithMessage(err, "get licenses failed")
+		}
+
+		return nil, licenses, nil
+	}
+}
@@ -0,0 +1,28 @@
+package tool
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+)
+
+type ListVersionsParams struct {
+	PkgName string `json:"pkgName" jsonschema:"the import path of the package or module"`
+}
+
+func GetListVersionsTool() mcp.ToolHandlerFor[ListVersionsParams, *godoc.VersionList] {
+	return func(ctx context.Context, c *mcp.CallToolRequest, input ListVersionsParams) (*mcp.CallToolResult, *godoc.VersionList, error) {
+		ctx = logging.WithSession(ctx, c.Session)
+		ctx = withProgress(ctx, c)
+		versions, err := godoc.ListVersions(ctx, input.PkgName)
+		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "list versions failed", "package", input.PkgName, "err", err)
+			return nil, nil, errors.WithMessage(err, "list versions failed")
+		}
+
+		return nil, versions, nil
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/pkg/errors"
 	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
 )
 
 type GetPkgInfoParams struct {
@@ -17,6 +18,9 @@ type GetPkgInfoParams struct {
 	// client, then if user want to get the client package info, you should set the packageName to
 	// github.com/mark3labs/mcp-go/mcp/client rather than client
 	PkgName string `json:"pkgName" jsonschema:"the package name user search"`
+	// version of the package, e.g. v1.2.3. empty means the latest. set it to the version in user's go.mod when user
+	// asks about a dependency of the project
+	Version string `json:"version,omitempty" jsonschema:"version of the package, empty means the latest"`
 	// default is false. if it`s true, will return the url of the definition of the package`s consts,types,functions,
 	// variables,subpackages. only when user need it, set it
 	NeedURL bool `json:"needURL" jsonschema:"if user need the link to the definition"`
@@ -24,11 +28,15 @@ type GetPkgInfoParams struct {
 
 func GetPkgInfoTool() mcp.ToolHandlerFor[GetPkgInfoParams, *godoc.PackageDocument] {
 	return func(ctx context.Context, c *mcp.CallToolRequest, input GetPkgInfoParams) (*mcp.CallToolResult, *godoc.PackageDocument, error) {
-		pkgDoc, err := godoc.GetPackageDocument(godoc.GetPackageRequest{
+		ctx = logging.WithSession(ctx, c.Session)
+		ctx = withProgress(ctx, c)
+		pkgDoc, err := godoc.GetPackageDocument(ctx, godoc.GetPackageRequest{
 			PackageName: input.PkgName,
+			Version:     input.Version,
 			NeedURL:     input.NeedURL,
 		})
 		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "get pkg info failed", "package", input.PkgName, "version", input.Version, "err", err)
 			return nil, nil, errors.WithMessage(err, "get pkg info failed")
 		}
 
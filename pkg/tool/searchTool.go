@@ -6,17 +6,43 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/pkg/errors"
 	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
 )
 
-type searchParams struct {
-	Q string `json:"q" jsonschema:"query string"`
-}
+type SearchParams struct {
+	Q     string `json:"q" jsonschema:"query string"`
+	Mode  string `json:"mode,omitempty" jsonschema:"package or symbol, empty means package"`
+	Limit int    `json:"limit,omitempty" jsonschema:"max number of results in one page, at most 100"`
+	Page  int    `json:"page,omitempty" jsonschema:"page number starting from 1"`
 
-func GetSearchTool() mcp.ToolHandlerFor[searchParams, *godoc.SearchResult] {
-	return func(ctx context.Context, c *mcp.CallToolRequest, input searchParams) (*mcp.CallToolResult, *godoc.SearchResult, error) {
+	ExcludeStd      bool     `json:"excludeStd,omitempty" jsonschema:"exclude packages of the standard library"`
+	ExcludeInternal bool     `json:"excludeInternal,omitempty" jsonschema:"exclude packages under internal/ or examples/"`
+	StableOnly      bool     `json:"stableOnly,omitempty" jsonschema:"only packages whose latest version is v1 or above"`
+	MinImportedBy   int      `json:"minImportedBy,omitempty" jsonschema:"exclude packages imported by fewer packages"`
+	Licenses        []string `json:"licenses,omitempty" jsonschema:"allowed license types, e.g. MIT"`
+	Rerank          bool     `json:"rerank,omitempty" jsonschema:"rerank by imported by count and recency"`
+}
 
-		search, err := godoc.Search(input.Q)
+func GetSearchTool() mcp.ToolHandlerFor[SearchParams, *godoc.SearchResult] {
+	return func(ctx context.Context, c *mcp.CallToolRequest, input SearchParams) (*mcp.CallToolResult, *godoc.SearchResult, error) {
+		ctx = logging.WithSession(ctx, c.Session)
+		ctx = withProgress(ctx, c)
+		search, err := godoc.Search(ctx, godoc.SearchRequest{
+			Query: input.Q,
+			Mode:  input.Mode,
+			Limit: input.Limit,
+			Page:  input.Page,
+			Filter: godoc.SearchFilter{
+				ExcludeStd:      input.ExcludeStd,
+				ExcludeInternal: input.ExcludeInternal,
+				StableOnly:      input.StableOnly,
+				MinImportedBy:   input.MinImportedBy,
+				Licenses:        input.Licenses,
+			},
+			Rerank: input.Rerank,
+		})
 		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "search failed", "query", input.Q, "mode", input.Mode, "page", input.Page, "err", err)
 			return nil, nil, errors.WithMessage(err, "search failed.")
 		}
 
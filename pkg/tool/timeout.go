@@ -0,0 +1,21 @@
+package tool
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// WithTimeout 给工具加上超时，超时后 ctx 会被取消，没有其他请求在等待同一个页面时对上游的请求也会中断
+// d <= 0 时不设置超时，只受客户端取消的影响
+func WithTimeout[In, Out any](h mcp.ToolHandlerFor[In, Out], d time.Duration) mcp.ToolHandlerFor[In, Out] {
+	if d <= 0 {
+		return h
+	}
+	return func(ctx context.Context, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, Out, error) {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return h(ctx, req, input)
+	}
+}
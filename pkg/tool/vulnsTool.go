@@ -0,0 +1,31 @@
+package tool
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
+	"github.com/yikakia/godoc-mcp-server/pkg/godoc"
+	"github.com/yikakia/godoc-mcp-server/pkg/logging"
+)
+
+type GetVulnsParams struct {
+	PkgName string `json:"pkgName" jsonschema:"the import path of the package or module"`
+	Version string `json:"version,omitempty" jsonschema:"version of the module, empty means all versions"`
+}
+
+func GetVulnsTool() mcp.ToolHandlerFor[GetVulnsParams, *godoc.VulnReport] {
+	return func(ctx context.Context, c *mcp.CallToolRequest, input GetVulnsParams) (*mcp.CallToolResult, *godoc.VulnReport, error) {
+		ctx = logging.WithSession(ctx, c.Session)
+		report, err := godoc.GetVulnerabilities(ctx, godoc.VulnRequest{
+			PackageName: input.PkgName,
+			Version:     input.Version,
+		})
+		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "get vulnerabilities failed", "package", input.PkgName, "version", input.Version, "err", err)
+			return nil, nil, errors.WithMessage(err, "get vulnerabilities failed")
+		}
+
+		return nil, report, nil
+	}
+}